@@ -0,0 +1,72 @@
+package mofu
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"sync"
+)
+
+// capturer is the interface implemented by matchers that store matched arguments.
+type capturer interface {
+	capture(v any)
+}
+
+// Capture is a [Matcher] that accepts any argument and stores it.
+// The zero value for Capture is ready to use.
+//
+// Passing a pointer to Capture to [Mock.When], it stores arguments of calls that match the condition.
+// It must be passed to [Mock.When] directly; [Mock.When] fails if it is nested in another matcher such as [AllOf].
+type Capture[T any] struct {
+	mu     sync.Mutex
+	values []T
+}
+
+// Match always returns true.
+func (c *Capture[T]) Match(v any) bool { return true }
+
+// Equal reports whether o is c itself.
+func (c *Capture[T]) Equal(o Matcher) bool {
+	p, ok := o.(*Capture[T])
+	return ok && p == c
+}
+
+func (c *Capture[T]) String() string {
+	return fmt.Sprintf("<capture %s>", reflect.TypeFor[T]())
+}
+
+func (c *Capture[T]) bindType(typ reflect.Type, o *options) (Matcher, error) {
+	if t := reflect.TypeFor[T](); !typ.AssignableTo(t) {
+		return nil, fmt.Errorf("cannot capture %s value into %s", typ, t)
+	}
+	return c, nil
+}
+
+func (c *Capture[T]) wildcard() {}
+
+func (c *Capture[T]) capture(v any) {
+	x, _ := v.(T)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.values = append(c.values, x)
+}
+
+// Last returns the last captured value. It returns the zero value if no value is captured.
+func (c *Capture[T]) Last() T {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var v T
+	if n := len(c.values); n > 0 {
+		v = c.values[n-1]
+	}
+	return v
+}
+
+// All returns all captured values in the order of calls.
+func (c *Capture[T]) All() []T {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return slices.Clone(c.values)
+}
+
+var _ Matcher = (*Capture[int])(nil)
@@ -0,0 +1,74 @@
+package mofu
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/m-mizutani/gt"
+)
+
+func TestCapture(t *testing.T) {
+	t.Run("matched calls only", func(t *testing.T) {
+		var c Capture[string]
+		m := MockFor[func(context.Context, string, int) int]()
+		m.When(Any, &c, 1).Return(1)
+		fn, _ := m.Make()
+		ctx := context.Background()
+		gt.Equal(t, fn(ctx, "a", 1), 1)
+		gt.Equal(t, fn(ctx, "b", 2), 0)
+		gt.Equal(t, fn(ctx, "c", 1), 1)
+		gt.Equal(t, c.Last(), "c")
+		gt.Equal(t, c.All(), []string{"a", "c"})
+	})
+	t.Run("empty", func(t *testing.T) {
+		var c Capture[int]
+		gt.Equal(t, c.Last(), 0)
+		gt.Equal(t, len(c.All()), 0)
+	})
+	t.Run("interface", func(t *testing.T) {
+		var c Capture[error]
+		m := MockFor[func(error)]()
+		m.When(&c)
+		fn, _ := m.Make()
+		fn(nil)
+		gt.Equal(t, c.All(), []error{nil})
+	})
+	t.Run("same capture returns the same condition", func(t *testing.T) {
+		var c1, c2 Capture[int]
+		m := MockFor[func(int)]()
+		gt.True(t, m.When(&c1) == m.When(&c1))
+		gt.True(t, m.When(&c1) != m.When(&c2))
+	})
+	t.Run("mismatched type", func(t *testing.T) {
+		defer func() {
+			e := recover()
+			gt.NotNil(t, e)
+		}()
+		var c Capture[int]
+		m := MockFor[func(string)]()
+		m.When(&c)
+	})
+	t.Run("nested", func(t *testing.T) {
+		var c Capture[string]
+		m := MockFor[func(string)]()
+		for _, p := range []Matcher{AllOf(HasPrefix("a"), &c), Not(&c)} {
+			func() {
+				defer func() {
+					e := recover()
+					gt.String(t, fmt.Sprint(e)).Contains("cannot use <capture string> in another matcher")
+				}()
+				m.When(p)
+			}()
+		}
+	})
+	t.Run("nested in variadic arguments", func(t *testing.T) {
+		defer func() {
+			e := recover()
+			gt.NotNil(t, e)
+		}()
+		var c Capture[any]
+		m := MockOf(fmt.Sprint)
+		m.When(Rest(&c))
+	})
+}
@@ -0,0 +1,387 @@
+package mofu
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// sliceMatcher is the interface implemented by matchers that accept a slice.
+//
+// If the last value passed to [Mock.When] for a variadic function is a sliceMatcher,
+// it matches all of the variadic arguments as a slice instead of a single element.
+// [AnyRest] and [Rest] also match the remaining variadic arguments after some of them.
+type sliceMatcher interface {
+	Matcher
+	acceptSlice()
+}
+
+func isSliceMatcher(v any) bool {
+	_, ok := v.(sliceMatcher)
+	return ok
+}
+
+func isRestMatcher(v any) bool {
+	switch v.(type) {
+	case anyRestMatcher, *restMatcher:
+		return true
+	default:
+		return false
+	}
+}
+
+// variadicTail is a matcher for the variadic arguments.
+type variadicTail struct {
+	m   Matcher
+	typ reflect.Type // slice type
+}
+
+// matchArgs reports whether the variadic arguments are acceptable.
+func (t *variadicTail) matchArgs(args []*typeval) bool {
+	s := reflect.MakeSlice(t.typ, len(args), len(args))
+	for i, arg := range args {
+		s.Index(i).Set(arg.val)
+	}
+	return t.Match(s.Interface())
+}
+
+// accepts reports whether t certainly accepts the variadic arguments that ms accept.
+func (t *variadicTail) accepts(ms []Matcher) bool {
+	if isWildcard(t) {
+		return true
+	}
+	if r, ok := t.m.(*restMatcher); ok {
+		for _, m := range ms {
+			if !overlap(r.m, m) {
+				return false
+			}
+		}
+		return true
+	}
+	args := make([]*typeval, len(ms))
+	for i, m := range ms {
+		v, ok := m.(*typeval)
+		if !ok {
+			return false
+		}
+		args[i] = v
+	}
+	return t.matchArgs(args)
+}
+
+func (t *variadicTail) Match(v any) bool { return t.m.Match(v) }
+
+func (t *variadicTail) Equal(o Matcher) bool {
+	p, ok := o.(*variadicTail)
+	return ok && t.m.Equal(p.m)
+}
+
+func (t *variadicTail) String() string { return t.m.String() + "..." }
+
+var _ Matcher = (*variadicTail)(nil)
+
+type anyRestMatcher int
+
+func (anyRestMatcher) Match(v any) bool     { return true }
+func (anyRestMatcher) Equal(o Matcher) bool { return o == AnyRest }
+func (anyRestMatcher) String() string       { return "<any>" }
+
+func (m anyRestMatcher) bindType(typ reflect.Type, o *options) (Matcher, error) {
+	if typ.Kind() != reflect.Slice {
+		return nil, fmt.Errorf("cannot use AnyRest for %s value; it must be the last argument for variadic arguments", typ)
+	}
+	return m, nil
+}
+
+func (anyRestMatcher) acceptSlice() {}
+func (anyRestMatcher) wildcard()    {}
+
+var _ sliceMatcher = (anyRestMatcher)(0)
+
+const (
+	// AnyRest accepts zero or more remaining arguments of a variadic function.
+	// It must be the last argument of [Mock.When], and placed at or after the variadic parameter.
+	AnyRest = anyRestMatcher(0)
+)
+
+type restMatcher struct {
+	x any
+	m Matcher
+}
+
+// Rest returns a [Matcher] that accepts zero or more remaining arguments of a variadic function
+// if x accepts each of them.
+// It must be the last argument of [Mock.When], and placed at or after the variadic parameter.
+//
+// X is either a value or a [Matcher].
+func Rest(x any) Matcher {
+	return &restMatcher{x: x}
+}
+
+func (m *restMatcher) Match(v any) bool {
+	if m.m == nil {
+		b, ok := bindDynamic(m, v)
+		return ok && b.Match(v)
+	}
+	p := reflect.ValueOf(v)
+	if p.Kind() != reflect.Slice {
+		return false
+	}
+	for i := range p.Len() {
+		if !m.m.Match(p.Index(i).Interface()) {
+			return false
+		}
+	}
+	return true
+}
+
+func (m *restMatcher) Equal(o Matcher) bool {
+	p, ok := o.(*restMatcher)
+	return ok && m.m.Equal(p.m)
+}
+
+func (m *restMatcher) String() string {
+	return m.m.String()
+}
+
+func (m *restMatcher) bindType(typ reflect.Type, o *options) (Matcher, error) {
+	if typ.Kind() != reflect.Slice {
+		return nil, fmt.Errorf("cannot use rest for %s value; it must be the last argument for variadic arguments", typ)
+	}
+	p, err := newMatcher(m.x, typ.Elem(), o)
+	if err != nil {
+		return nil, err
+	}
+	return &restMatcher{m.x, p}, nil
+}
+
+func (m *restMatcher) acceptSlice() {}
+
+var _ sliceMatcher = (*restMatcher)(nil)
+
+type elementsMatcher struct {
+	elems []any
+	ms    []Matcher
+}
+
+// ElementsMatch returns a [Matcher] that accepts a slice or an array
+// if its elements are accepted by elems in any order.
+// If it is passed to [Mock.When] as the last argument at the variadic parameter,
+// it matches all of the variadic arguments as a slice.
+//
+// Each of elems is either a value or a [Matcher].
+func ElementsMatch(elems ...any) Matcher {
+	return &elementsMatcher{elems: elems}
+}
+
+func (m *elementsMatcher) Match(v any) bool {
+	if m.ms == nil {
+		b, ok := bindDynamic(m, v)
+		return ok && b.Match(v)
+	}
+	p := reflect.ValueOf(v)
+	if !p.IsValid() || p.Len() != len(m.ms) {
+		return false
+	}
+
+	// find a perfect matching between elements and matchers
+	owner := make([]int, len(m.ms)) // matcher index -> element index
+	for i := range owner {
+		owner[i] = -1
+	}
+	var assign func(i int, seen []bool) bool
+	assign = func(i int, seen []bool) bool {
+		e := p.Index(i).Interface()
+		for j, x := range m.ms {
+			if seen[j] || !x.Match(e) {
+				continue
+			}
+			seen[j] = true
+			if owner[j] < 0 || assign(owner[j], seen) {
+				owner[j] = i
+				return true
+			}
+		}
+		return false
+	}
+	for i := range p.Len() {
+		if !assign(i, make([]bool, len(m.ms))) {
+			return false
+		}
+	}
+	return true
+}
+
+func (m *elementsMatcher) Equal(o Matcher) bool {
+	p, ok := o.(*elementsMatcher)
+	return ok && equalMatchers(m.ms, p.ms)
+}
+
+func (m *elementsMatcher) String() string {
+	return fmt.Sprintf("elementsMatch(%s)", joinMatchers(m.ms))
+}
+
+func (m *elementsMatcher) bindType(typ reflect.Type, o *options) (Matcher, error) {
+	if k := typ.Kind(); k != reflect.Slice && k != reflect.Array {
+		return nil, fmt.Errorf("cannot use elementsMatch for %s value", typ)
+	}
+	ms, err := newMatchers(m.elems, typ.Elem(), o)
+	if err != nil {
+		return nil, err
+	}
+	return &elementsMatcher{m.elems, ms}, nil
+}
+
+func (m *elementsMatcher) acceptSlice() {}
+
+var _ sliceMatcher = (*elementsMatcher)(nil)
+
+type containsMatcher struct {
+	x any
+	m Matcher
+}
+
+// ContainsElement returns a [Matcher] that accepts a slice, an array or a map
+// if any of its elements is accepted by x. For a map, it examines values of the map.
+// If it is passed to [Mock.When] as the last argument at the variadic parameter,
+// it matches all of the variadic arguments as a slice.
+//
+// X is either a value or a [Matcher].
+func ContainsElement(x any) Matcher {
+	return &containsMatcher{x: x}
+}
+
+func (m *containsMatcher) Match(v any) bool {
+	if m.m == nil {
+		b, ok := bindDynamic(m, v)
+		return ok && b.Match(v)
+	}
+	p := reflect.ValueOf(v)
+	switch p.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := range p.Len() {
+			if m.m.Match(p.Index(i).Interface()) {
+				return true
+			}
+		}
+	case reflect.Map:
+		for iter := p.MapRange(); iter.Next(); {
+			if m.m.Match(iter.Value().Interface()) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (m *containsMatcher) Equal(o Matcher) bool {
+	p, ok := o.(*containsMatcher)
+	return ok && m.m.Equal(p.m)
+}
+
+func (m *containsMatcher) String() string {
+	return fmt.Sprintf("containsElement(%s)", m.m)
+}
+
+func (m *containsMatcher) bindType(typ reflect.Type, o *options) (Matcher, error) {
+	switch typ.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map:
+	default:
+		return nil, fmt.Errorf("cannot use containsElement for %s value", typ)
+	}
+	p, err := newMatcher(m.x, typ.Elem(), o)
+	if err != nil {
+		return nil, err
+	}
+	return &containsMatcher{m.x, p}, nil
+}
+
+func (m *containsMatcher) acceptSlice() {}
+
+var _ sliceMatcher = (*containsMatcher)(nil)
+
+type lenMatcher int
+
+// Len returns a [Matcher] that accepts a slice, an array, a map, a channel or a string
+// if its length is n.
+// If it is passed to [Mock.When] as the last argument at the variadic parameter,
+// it matches the number of the variadic arguments.
+func Len(n int) Matcher {
+	return lenMatcher(n)
+}
+
+func (n lenMatcher) Match(v any) bool {
+	p := reflect.ValueOf(v)
+	switch p.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map, reflect.Chan, reflect.String:
+		return p.Len() == int(n)
+	default:
+		return false
+	}
+}
+
+func (n lenMatcher) Equal(o Matcher) bool { return o == n }
+func (n lenMatcher) String() string       { return fmt.Sprintf("len(%d)", int(n)) }
+
+func (n lenMatcher) bindType(typ reflect.Type, o *options) (Matcher, error) {
+	switch typ.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map, reflect.Chan, reflect.String:
+		return n, nil
+	default:
+		return nil, fmt.Errorf("cannot use %s for %s value", n, typ)
+	}
+}
+
+func (lenMatcher) acceptSlice() {}
+
+var _ sliceMatcher = (lenMatcher)(0)
+
+type keyMatcher struct {
+	k any
+	m Matcher
+}
+
+// HasKey returns a [Matcher] that accepts a map if any of its keys is accepted by k.
+//
+// K is either a value or a [Matcher].
+func HasKey(k any) Matcher {
+	return &keyMatcher{k: k}
+}
+
+func (m *keyMatcher) Match(v any) bool {
+	if m.m == nil {
+		b, ok := bindDynamic(m, v)
+		return ok && b.Match(v)
+	}
+	p := reflect.ValueOf(v)
+	if p.Kind() != reflect.Map {
+		return false
+	}
+	for iter := p.MapRange(); iter.Next(); {
+		if m.m.Match(iter.Key().Interface()) {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *keyMatcher) Equal(o Matcher) bool {
+	p, ok := o.(*keyMatcher)
+	return ok && m.m.Equal(p.m)
+}
+
+func (m *keyMatcher) String() string {
+	return fmt.Sprintf("hasKey(%s)", m.m)
+}
+
+func (m *keyMatcher) bindType(typ reflect.Type, o *options) (Matcher, error) {
+	if typ.Kind() != reflect.Map {
+		return nil, fmt.Errorf("cannot use hasKey for %s value", typ)
+	}
+	p, err := newMatcher(m.k, typ.Key(), o)
+	if err != nil {
+		return nil, err
+	}
+	return &keyMatcher{m.k, p}, nil
+}
+
+var _ Matcher = (*keyMatcher)(nil)
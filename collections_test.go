@@ -0,0 +1,170 @@
+package mofu
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/m-mizutani/gt"
+)
+
+func TestElementsMatch(t *testing.T) {
+	t.Run("slice", func(t *testing.T) {
+		m := MockFor[func([]string) int]()
+		m.When(ElementsMatch("a", "b", HasPrefix("c"))).Return(1)
+		fn, _ := m.Make()
+		gt.Equal(t, fn([]string{"cat", "b", "a"}), 1)
+		gt.Equal(t, fn([]string{"a", "b"}), 0)
+		gt.Equal(t, fn([]string{"a", "b", "d"}), 0)
+	})
+	t.Run("overlapped matchers", func(t *testing.T) {
+		m := MockFor[func([]string) int]()
+		m.When(ElementsMatch(HasPrefix("a"), "ab")).Return(1)
+		fn, _ := m.Make()
+		gt.Equal(t, fn([]string{"ab", "ac"}), 1)
+		gt.Equal(t, fn([]string{"ac", "ac"}), 0)
+	})
+	t.Run("variadic arguments", func(t *testing.T) {
+		m := MockOf(fmt.Sprint)
+		m.When(ElementsMatch(1, 2)).Return("ok")
+		fn, _ := m.Make()
+		gt.Equal(t, fn(2, 1), "ok")
+		gt.Equal(t, fn(1), "")
+	})
+}
+
+func TestContainsElement(t *testing.T) {
+	t.Run("slice", func(t *testing.T) {
+		m := MockFor[func([]int) int]()
+		m.When(ContainsElement(GreaterThan(10))).Return(1)
+		fn, _ := m.Make()
+		gt.Equal(t, fn([]int{1, 11}), 1)
+		gt.Equal(t, fn([]int{1, 10}), 0)
+	})
+	t.Run("map", func(t *testing.T) {
+		m := MockFor[func(map[string]int) int]()
+		m.When(ContainsElement(3)).Return(1)
+		fn, _ := m.Make()
+		gt.Equal(t, fn(map[string]int{"a": 3}), 1)
+		gt.Equal(t, fn(map[string]int{"a": 1}), 0)
+	})
+	t.Run("variadic arguments", func(t *testing.T) {
+		type logFunc func(format string, args ...any)
+		m := MockFor[logFunc]()
+		m.When("%s: %v", ContainsElement("err")).Return()
+		fn, r := m.Make()
+		fn("%s: %v", "err", 1)
+		gt.Equal(t, r.Count(), 1)
+	})
+}
+
+func TestLen(t *testing.T) {
+	t.Run("variadic arguments", func(t *testing.T) {
+		m := MockOf(fmt.Sprint)
+		m.When(Len(3)).Return("3")
+		m.When(Len(0)).Return("0")
+		fn, _ := m.Make()
+		gt.Equal(t, fn(1, 2, 3), "3")
+		gt.Equal(t, fn(), "0")
+		gt.Equal(t, fn(1, 2), "")
+	})
+	t.Run("map", func(t *testing.T) {
+		m := MockFor[func(map[string]int) int]()
+		m.When(Len(1)).Return(1)
+		fn, _ := m.Make()
+		gt.Equal(t, fn(map[string]int{"a": 1}), 1)
+		gt.Equal(t, fn(nil), 0)
+	})
+	t.Run("same pattern returns the same condition", func(t *testing.T) {
+		m := MockOf(fmt.Sprint)
+		c := m.When(Len(1))
+		gt.True(t, c == m.When(Len(1)))
+		gt.True(t, c != m.When(Len(2)))
+		gt.String(t, c.pattern[0].String()).Equal("len(1)...")
+	})
+	t.Run("not a collection", func(t *testing.T) {
+		defer func() {
+			e := recover()
+			gt.NotNil(t, e)
+		}()
+		m := MockFor[func(int)]()
+		m.When(Len(1))
+	})
+}
+
+func TestHasKey(t *testing.T) {
+	m := MockFor[func(map[string]int) int]()
+	m.When(HasKey(HasPrefix("x-"))).Return(1)
+	fn, _ := m.Make()
+	gt.Equal(t, fn(map[string]int{"x-id": 1}), 1)
+	gt.Equal(t, fn(map[string]int{"id": 1}), 0)
+}
+
+func TestCollectionMatcher_Match(t *testing.T) {
+	gt.True(t, ElementsMatch(2, 1).Match([]int{1, 2}))
+	gt.False(t, ElementsMatch(2, 1).Match([]int{1, 3}))
+	gt.False(t, ElementsMatch(1).Match(1))
+	gt.True(t, ContainsElement(HasPrefix("a")).Match(map[int]string{1: "ab"}))
+	gt.False(t, ContainsElement(1).Match([]int{2}))
+	gt.True(t, HasKey("a").Match(map[string]int{"a": 1}))
+	gt.False(t, HasKey("a").Match(nil))
+}
+
+func TestRest(t *testing.T) {
+	type logFunc func(level, format string, args ...any) int
+	t.Run("any rest", func(t *testing.T) {
+		m := MockFor[logFunc]()
+		m.When("INFO", Any, AnyRest).Return(1)
+		fn, _ := m.Make()
+		gt.Equal(t, fn("INFO", "hello"), 1)
+		gt.Equal(t, fn("INFO", "%s %d", "a", 1), 1)
+		gt.Equal(t, fn("WARN", "hello"), 0)
+	})
+	t.Run("each element", func(t *testing.T) {
+		m := MockFor[logFunc]()
+		m.When(Any, Any, Rest(AnyOfType[string]())).Return(1)
+		fn, _ := m.Make()
+		gt.Equal(t, fn("INFO", "%s %s", "a", "b"), 1)
+		gt.Equal(t, fn("INFO", "msg"), 1)
+		gt.Equal(t, fn("INFO", "%s %d", "a", 1), 0)
+	})
+	t.Run("same pattern returns the same condition", func(t *testing.T) {
+		m := MockFor[logFunc]()
+		c := m.When("INFO", Any, AnyRest)
+		gt.True(t, c == m.When("INFO", Any, AnyRest))
+		gt.True(t, c != m.When("INFO", Any))
+		gt.True(t, c != m.When("INFO", Any, Rest(Any)))
+		gt.String(t, c.pattern[2].String()).Equal("<any>...")
+	})
+	t.Run("not variadic", func(t *testing.T) {
+		defer func() {
+			e := recover()
+			gt.NotNil(t, e)
+		}()
+		m := MockFor[func(string)]()
+		m.When(AnyRest)
+	})
+	t.Run("after variadic arguments", func(t *testing.T) {
+		m := MockOf(fmt.Sprintf)
+		m.When("%s-%d", "a", AnyRest).Return("1")
+		m.When("%s", "b", Rest(AnyOfType[int]())).Return("2")
+		fn, _ := m.Make()
+		gt.Equal(t, fn("%s-%d", "a"), "1")
+		gt.Equal(t, fn("%s-%d", "a", 1, 2), "1")
+		gt.Equal(t, fn("%s-%d", "b", 1), "")
+		gt.Equal(t, fn("%s", "b", 1, 2), "2")
+		gt.Equal(t, fn("%s", "b", "c"), "")
+	})
+	t.Run("misplaced", func(t *testing.T) {
+		defer func() {
+			e := recover()
+			gt.String(t, fmt.Sprint(e)).Contains("it must be the last argument for variadic arguments")
+		}()
+		m := MockOf(fmt.Sprintf)
+		m.When("%s", AnyRest, "a")
+	})
+	t.Run("direct call", func(t *testing.T) {
+		gt.True(t, Rest("a").Match([]string{"a", "a"}))
+		gt.False(t, Rest("a").Match([]string{"a", "b"}))
+		gt.False(t, Rest("a").Match("a"))
+	})
+}
@@ -1,40 +1,477 @@
 package mofu
 
-import "reflect"
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// Matcher is the interface that matches an argument of the mock function.
+//
+// Any value implementing Matcher can be passed to [Mock.When] at any argument position.
+//
+// Matchers of this package can also be called directly, for instance, from another Matcher.
+// Then a matcher that depends on the argument type is bound to the dynamic type of v on each call,
+// and rejects v if it cannot be used for the type.
+type Matcher interface {
+	// Match reports whether v is acceptable as the argument.
+	Match(v any) bool
+
+	// Equal reports whether o is the same pattern as the matcher.
+	// It is used to identify the existing [Cond] in [Mock.When].
+	Equal(o Matcher) bool
+
+	// String returns the description of the matcher.
+	String() string
+}
 
 type anyMatcher int
 
-func (anyMatcher) canAccept(arg *typeval) bool { return true }
-func (anyMatcher) equal(o condExpr) bool       { return o == Any }
-func (anyMatcher) String() string              { return "<any>" }
+func (anyMatcher) Match(v any) bool     { return true }
+func (anyMatcher) Equal(o Matcher) bool { return o == Any }
+func (anyMatcher) String() string       { return "<any>" }
+func (anyMatcher) wildcard()            {}
 
-var _ condExpr = (anyMatcher)(0)
+var _ Matcher = (anyMatcher)(0)
 
 const (
 	Any = anyMatcher(0)
 )
 
+type shapeMatcher int
+
+func (s shapeMatcher) Match(v any) bool {
+	switch s {
+	case Nil, NotNil:
+		return isNil(v) == (s == Nil)
+	case Zero:
+		return v == nil || reflect.ValueOf(v).IsZero()
+	default:
+		panic("unknown shape")
+	}
+}
+
+func (s shapeMatcher) Equal(o Matcher) bool { return o == s }
+
+func (s shapeMatcher) String() string {
+	switch s {
+	case Nil:
+		return "<nil>"
+	case NotNil:
+		return "<not nil>"
+	case Zero:
+		return "<zero>"
+	default:
+		panic("unknown shape")
+	}
+}
+
+func (s shapeMatcher) bindType(typ reflect.Type, o *options) (Matcher, error) {
+	if s != Zero && typ.Kind() != reflect.Interface && !isNilable(typ) {
+		return nil, fmt.Errorf("cannot use %s for %s value", s, typ)
+	}
+	return &boundShape{s, typ}, nil
+}
+
+// boundShape is a shapeMatcher bound to the argument type.
+type boundShape struct {
+	shapeMatcher
+	typ reflect.Type
+}
+
+func (s *boundShape) Match(v any) bool {
+	if s.typ.Kind() == reflect.Interface {
+		// v holds the dynamic value, so the interface is nil (and zero) only if v is nil.
+		return (v == nil) == (s.shapeMatcher != NotNil)
+	}
+	return s.shapeMatcher.Match(v)
+}
+
+func (s *boundShape) Equal(o Matcher) bool {
+	p, ok := o.(*boundShape)
+	return ok && s.shapeMatcher == p.shapeMatcher && s.typ == p.typ
+}
+
+func isNilable(typ reflect.Type) bool {
+	switch typ.Kind() {
+	case reflect.Pointer, reflect.Slice, reflect.Map, reflect.Chan, reflect.Func, reflect.UnsafePointer:
+		return true
+	default:
+		return false
+	}
+}
+
+func isNil(v any) bool {
+	if v == nil {
+		return true
+	}
+	p := reflect.ValueOf(v)
+	return isNilable(p.Type()) && p.IsNil()
+}
+
+var (
+	_ Matcher = (shapeMatcher)(0)
+	_ Matcher = (*boundShape)(nil)
+)
+
+const (
+	// Nil accepts a nil argument. The argument type must be nilable.
+	// For an interface type, an interface holding a typed nil such as (*T)(nil) is not nil.
+	Nil = shapeMatcher(iota)
+
+	// NotNil accepts a non-nil argument. The argument type must be nilable.
+	NotNil
+
+	// Zero accepts the zero value of the argument type.
+	Zero
+)
+
+type typeMatcher[T any] struct{}
+
+// AnyOfType returns a [Matcher] that accepts an argument if its dynamic type is T or it implements T.
+//
+// T must be assignable to the argument type of the mock function.
+func AnyOfType[T any]() Matcher {
+	return typeMatcher[T]{}
+}
+
+func (typeMatcher[T]) Match(v any) bool {
+	_, ok := v.(T)
+	return ok
+}
+
+func (typeMatcher[T]) Equal(o Matcher) bool {
+	_, ok := o.(typeMatcher[T])
+	return ok
+}
+
+func (typeMatcher[T]) String() string {
+	return fmt.Sprintf("<type %s>", reflect.TypeFor[T]())
+}
+
+func (m typeMatcher[T]) bindType(typ reflect.Type, o *options) (Matcher, error) {
+	if t := reflect.TypeFor[T](); !t.AssignableTo(typ) {
+		return nil, fmt.Errorf("%s is not assignable to %s", t, typ)
+	}
+	return m, nil
+}
+
+var _ Matcher = typeMatcher[int]{}
+
+type notMatcher struct {
+	v any
+	m Matcher
+}
+
+// Not returns a [Matcher] that accepts an argument if x does not accept it.
+//
+// X is either a value or a [Matcher].
+func Not(x any) Matcher {
+	return &notMatcher{v: x}
+}
+
+func (n *notMatcher) Match(v any) bool {
+	if n.m == nil {
+		m, ok := bindDynamic(n, v)
+		return ok && m.Match(v)
+	}
+	return !n.m.Match(v)
+}
+
+func (n *notMatcher) Equal(o Matcher) bool {
+	p, ok := o.(*notMatcher)
+	return ok && n.m.Equal(p.m)
+}
+
+func (n *notMatcher) String() string {
+	return fmt.Sprintf("not(%s)", n.m)
+}
+
+func (n *notMatcher) bindType(typ reflect.Type, o *options) (Matcher, error) {
+	m, err := newMatcher(n.v, typ, o)
+	if err != nil {
+		return nil, err
+	}
+	return &notMatcher{n.v, m}, nil
+}
+
+var _ Matcher = (*notMatcher)(nil)
+
+type logicalMatcher struct {
+	op   string // "allOf" or "anyOf"
+	args []any
+	ms   []Matcher
+}
+
+// AllOf returns a [Matcher] that accepts an argument if all of args accept it.
+//
+// Each of args is either a value or a [Matcher].
+func AllOf(args ...any) Matcher {
+	return &logicalMatcher{op: "allOf", args: args}
+}
+
+// AnyOf returns a [Matcher] that accepts an argument if any of args accepts it.
+//
+// Each of args is either a value or a [Matcher].
+func AnyOf(args ...any) Matcher {
+	return &logicalMatcher{op: "anyOf", args: args}
+}
+
+func (l *logicalMatcher) Match(v any) bool {
+	if l.ms == nil {
+		m, ok := bindDynamic(l, v)
+		return ok && m.Match(v)
+	}
+	all := l.op == "allOf"
+	for _, m := range l.ms {
+		if m.Match(v) != all {
+			return !all
+		}
+	}
+	return all
+}
+
+func (l *logicalMatcher) Equal(o Matcher) bool {
+	p, ok := o.(*logicalMatcher)
+	return ok && l.op == p.op && equalMatchers(l.ms, p.ms)
+}
+
+func (l *logicalMatcher) String() string {
+	return fmt.Sprintf("%s(%s)", l.op, joinMatchers(l.ms))
+}
+
+func (l *logicalMatcher) bindType(typ reflect.Type, o *options) (Matcher, error) {
+	ms, err := newMatchers(l.args, typ, o)
+	if err != nil {
+		return nil, err
+	}
+	return &logicalMatcher{l.op, l.args, ms}, nil
+}
+
+var _ Matcher = (*logicalMatcher)(nil)
+
 type typeval struct {
 	typ reflect.Type
 	val reflect.Value
+
+	cmpOpts []cmp.Option // compare with reflect.DeepEqual if empty
 }
 
-func (tv *typeval) canAccept(arg *typeval) bool {
-	if tv.typ != arg.typ {
-		return false
+func (tv *typeval) Match(v any) bool {
+	if len(tv.cmpOpts) > 0 {
+		return cmp.Equal(tv.val.Interface(), v, tv.cmpOpts...)
 	}
-	v1 := tv.val.Interface()
-	v2 := arg.val.Interface()
-	return reflect.DeepEqual(v1, v2)
+	return reflect.DeepEqual(tv.val.Interface(), v)
 }
 
-func (tv *typeval) equal(o condExpr) bool {
+func (tv *typeval) Equal(o Matcher) bool {
 	p, ok := o.(*typeval)
-	return ok && tv.canAccept(p)
+	return ok && tv.typ == p.typ && tv.Match(p.val.Interface())
+}
+
+func (tv *typeval) String() string {
+	return fmt.Sprintf("%#v", tv.val.Interface())
 }
 
-var _ condExpr = (*typeval)(nil)
+var _ Matcher = (*typeval)(nil)
 
 func newTypeval(v reflect.Value) *typeval {
-	return &typeval{v.Type(), v}
+	return &typeval{typ: v.Type(), val: v}
+}
+
+// typeBinder is the interface implemented by matchers that depend on the argument type.
+//
+// The bindType method checks whether the matcher can accept values of type typ,
+// then returns the matcher to use for the argument.
+type typeBinder interface {
+	bindType(typ reflect.Type, o *options) (Matcher, error)
+}
+
+// bindDynamic binds b to the dynamic type of v.
+// It is used by matchers called directly, not through [Mock.When].
+func bindDynamic(b typeBinder, v any) (Matcher, bool) {
+	typ := reflect.TypeOf(v)
+	if typ == nil {
+		typ = reflect.TypeFor[any]()
+	}
+	m, err := b.bindType(typ, &options{})
+	return m, err == nil
+}
+
+// newMatcher returns a Matcher for the argument of type typ.
+// If v is not a Matcher, it will be compared with the argument as a value.
+func newMatcher(v any, typ reflect.Type, o *options) (Matcher, error) {
+	switch v := v.(type) {
+	case capturer:
+		return nil, fmt.Errorf("cannot use %s in another matcher", v)
+	case typeBinder:
+		return v.bindType(typ, o)
+	case Matcher:
+		return v, nil
+	default:
+		p, err := checkTypeval(v, typ)
+		if err != nil {
+			return nil, err
+		}
+		if len(o.cmpOpts) > 0 {
+			if err := checkCmpOptions(p.val.Interface(), o.cmpOpts); err != nil {
+				return nil, err
+			}
+			p.cmpOpts = o.cmpOpts
+		}
+		return p, nil
+	}
+}
+
+// checkCmpOptions returns an error if cmp.Equal panics on v with opts,
+// for instance, v is a struct that has unexported fields opts do not handle.
+func checkCmpOptions(v any, opts []cmp.Option) (err error) {
+	defer func() {
+		if e := recover(); e != nil {
+			err = fmt.Errorf("cannot compare %T with cmp options: %v", v, e)
+		}
+	}()
+	cmp.Equal(v, v, opts...)
+	return nil
+}
+
+func newMatchers(values []any, typ reflect.Type, o *options) ([]Matcher, error) {
+	ms := make([]Matcher, len(values))
+	for i, v := range values {
+		m, err := newMatcher(v, typ, o)
+		if err != nil {
+			return nil, err
+		}
+		ms[i] = m
+	}
+	return ms, nil
+}
+
+func equalMatchers(a, b []Matcher) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i, m := range a {
+		if !m.Equal(b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func joinMatchers(ms []Matcher) string {
+	a := make([]string, len(ms))
+	for i, m := range ms {
+		a[i] = m.String()
+	}
+	return strings.Join(a, ", ")
+}
+
+// wildcard is the interface implemented by matchers that accept all values.
+type wildcard interface {
+	wildcard()
+}
+
+func isWildcard(m Matcher) bool {
+	if t, ok := m.(*variadicTail); ok {
+		m = t.m
+	}
+	_, ok := m.(wildcard)
+	return ok
+}
+
+// overlapMatchers reports whether there are arguments that both pattern p1 and p2 certainly accept.
+func overlapMatchers(p1, p2 []Matcher) bool {
+	s, stail := splitTail(p1)
+	l, ltail := splitTail(p2)
+	if len(s) > len(l) {
+		s, stail, l, ltail = l, ltail, s, stail
+	}
+	if len(s) != len(l) && stail == nil {
+		return false
+	}
+	for i, m := range s {
+		if !overlap(m, l[i]) {
+			return false
+		}
+	}
+	// Both accept the arguments of len(l) if stail accepts the rest of l and ltail accepts nothing.
+	if stail != nil && !stail.accepts(l[len(s):]) {
+		return false
+	}
+	return ltail == nil || ltail.accepts(nil)
+}
+
+// splitTail returns the matchers for fixed arguments and the matcher for variadic arguments in pattern.
+// If pattern does not have a matcher for variadic arguments, it returns the entire pattern and nil.
+func splitTail(pattern []Matcher) ([]Matcher, *variadicTail) {
+	n := len(pattern)
+	if n == 0 {
+		return nil, nil
+	}
+	if tail, ok := pattern[n-1].(*variadicTail); ok {
+		return pattern[:n-1], tail
+	}
+	return pattern, nil
+}
+
+func overlap(m1, m2 Matcher) bool {
+	if isWildcard(m1) || isWildcard(m2) || m1.Equal(m2) {
+		return true
+	}
+	if v, ok := m1.(*typeval); ok {
+		return m2.Match(v.val.Interface())
+	}
+	if v, ok := m2.(*typeval); ok {
+		return m1.Match(v.val.Interface())
+	}
+	return false
+}
+
+type predicate[T any] struct {
+	fn func(T) bool
+}
+
+// Match returns a [Matcher] that accepts an argument if fn returns true.
+//
+// The argument type of the mock function must be assignable to T.
+func Match[T any](fn func(T) bool) Matcher {
+	return &predicate[T]{fn}
+}
+
+func (p *predicate[T]) Match(v any) bool {
+	x, ok := v.(T)
+	if !ok && v != nil {
+		return false
+	}
+	return p.fn(x)
+}
+
+func (p *predicate[T]) Equal(o Matcher) bool {
+	q, ok := o.(*predicate[T])
+	return ok && p == q
+}
+
+func (p *predicate[T]) String() string {
+	return fmt.Sprintf("<func(%s) bool>", reflect.TypeFor[T]())
+}
+
+func (p *predicate[T]) bindType(typ reflect.Type, o *options) (Matcher, error) {
+	if t := reflect.TypeFor[T](); !typ.AssignableTo(t) {
+		return nil, fmt.Errorf("cannot use func(%s) bool as a matcher for %s", t, typ)
+	}
+	return p, nil
+}
+
+var _ Matcher = (*predicate[int])(nil)
+
+// formatArgs returns the string representation of args.
+func formatArgs(args []*typeval) string {
+	a := make([]string, len(args))
+	for i, arg := range args {
+		a[i] = arg.String()
+	}
+	return strings.Join(a, ", ")
 }
@@ -0,0 +1,225 @@
+package mofu
+
+import (
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/m-mizutani/gt"
+)
+
+type lowerMatcher struct{}
+
+func (lowerMatcher) Match(v any) bool {
+	s, ok := v.(string)
+	return ok && strings.ToLower(s) == s
+}
+func (lowerMatcher) Equal(o Matcher) bool { return o == lowerMatcher{} }
+func (lowerMatcher) String() string       { return "<lower>" }
+
+func TestMatcher(t *testing.T) {
+	t.Run("custom matcher", func(t *testing.T) {
+		m := MockFor[func(string) int]()
+		m.When(lowerMatcher{}).Return(1)
+		fn, _ := m.Make()
+		gt.Equal(t, fn("abc"), 1)
+		gt.Equal(t, fn("ABC"), 0)
+	})
+	t.Run("same matcher returns the same condition", func(t *testing.T) {
+		m := MockFor[func(string) int]()
+		c1 := m.When(lowerMatcher{})
+		c2 := m.When(lowerMatcher{})
+		gt.True(t, c1 == c2)
+	})
+	t.Run("description", func(t *testing.T) {
+		gt.String(t, Any.String()).Equal("<any>")
+		m := MockFor[func(string, int)]()
+		c := m.When("a", 1)
+		gt.String(t, c.pattern[0].String()).Equal(`"a"`)
+		gt.String(t, c.pattern[1].String()).Equal("1")
+	})
+}
+
+func TestMatch(t *testing.T) {
+	t.Run("match", func(t *testing.T) {
+		m := MockFor[func(string, int) int]()
+		m.When(Match(func(s string) bool {
+			return strings.HasPrefix(s, "a")
+		}), 1).ReturnOnce(1).ReturnOnce(2)
+		fn, r := m.Make()
+		gt.Equal(t, fn("abc", 1), 1)
+		gt.Equal(t, fn("bcd", 1), 0)
+		gt.Equal(t, fn("acd", 1), 2)
+		gt.Equal(t, r.Count(), 3)
+	})
+	t.Run("interface", func(t *testing.T) {
+		m := MockFor[func(error) int]()
+		m.When(Match(func(err error) bool {
+			return err == nil
+		})).Return(1)
+		fn, _ := m.Make()
+		gt.Equal(t, fn(nil), 1)
+		gt.Equal(t, fn(io.EOF), 0)
+	})
+	t.Run("same matcher returns the same condition", func(t *testing.T) {
+		m := MockFor[func(int)]()
+		p := Match(func(int) bool { return true })
+		gt.True(t, m.When(p) == m.When(p))
+	})
+	t.Run("mismatched type", func(t *testing.T) {
+		defer func() {
+			e := recover()
+			gt.NotNil(t, e)
+		}()
+		m := MockFor[func(string)]()
+		m.When(Match(func(int) bool { return true }))
+	})
+}
+
+func TestNot(t *testing.T) {
+	m := MockFor[func(string) int]()
+	m.When(Not("a")).Return(1)
+	fn, _ := m.Make()
+	gt.Equal(t, fn("a"), 0)
+	gt.Equal(t, fn("b"), 1)
+}
+
+func TestAllOf(t *testing.T) {
+	m := MockFor[func(int) int]()
+	m.When(AllOf(Not(1), Not(2))).Return(1)
+	fn, _ := m.Make()
+	gt.Equal(t, fn(1), 0)
+	gt.Equal(t, fn(2), 0)
+	gt.Equal(t, fn(3), 1)
+}
+
+func TestAnyOf(t *testing.T) {
+	t.Run("match", func(t *testing.T) {
+		m := MockFor[func(string) int]()
+		m.When(AnyOf("a", "b")).ReturnOnce(1).ReturnOnce(2)
+		fn, _ := m.Make()
+		gt.Equal(t, fn("b"), 1)
+		gt.Equal(t, fn("c"), 0)
+		gt.Equal(t, fn("a"), 2)
+	})
+	t.Run("same pattern returns the same condition", func(t *testing.T) {
+		m := MockFor[func(string)]()
+		gt.True(t, m.When(AnyOf("a", "b")) == m.When(AnyOf("a", "b")))
+		gt.True(t, m.When(AnyOf("a", "b")) != m.When(AllOf("a", "b")))
+		gt.True(t, m.When(AnyOf("a", "b")) != m.When(AnyOf("a")))
+	})
+	t.Run("description", func(t *testing.T) {
+		m := MockFor[func(string)]()
+		c := m.When(AnyOf("a", Not(Any)))
+		gt.String(t, c.pattern[0].String()).Equal(`anyOf("a", not(<any>))`)
+	})
+	t.Run("mismatched type", func(t *testing.T) {
+		defer func() {
+			e := recover()
+			gt.NotNil(t, e)
+		}()
+		m := MockFor[func(string)]()
+		m.When(AnyOf("a", 1))
+	})
+}
+
+func TestLogicalMatcher_Match(t *testing.T) {
+	gt.False(t, AllOf(1, 2).Match(3))
+	gt.True(t, AllOf(1, Not(2)).Match(1))
+	gt.True(t, AnyOf(1).Match(1))
+	gt.False(t, AnyOf(1).Match("1"))
+	gt.True(t, Not(1).Match(2))
+	gt.False(t, Not(1).Match(1))
+	gt.False(t, Not(HasPrefix("a")).Match(1))
+}
+
+func TestNil(t *testing.T) {
+	t.Run("interface", func(t *testing.T) {
+		m := MockFor[func(error) int]()
+		m.When(Nil).Return(1)
+		m.When(NotNil).Return(2)
+		fn, _ := m.Make()
+		gt.Equal(t, fn(nil), 1)
+		gt.Equal(t, fn(io.EOF), 2)
+	})
+	t.Run("pointer", func(t *testing.T) {
+		m := MockFor[func(*int) int]()
+		m.When(NotNil).Return(2)
+		fn, _ := m.Make()
+		gt.Equal(t, fn(nil), 0)
+		gt.Equal(t, fn(new(int)), 2)
+	})
+	t.Run("any", func(t *testing.T) {
+		m := MockFor[func(any) int]()
+		m.When(NotNil).Return(2)
+		fn, _ := m.Make()
+		gt.Equal(t, fn(nil), 0)
+		gt.Equal(t, fn((*int)(nil)), 2)
+		gt.Equal(t, fn(1), 2)
+	})
+	t.Run("typed nil in interface", func(t *testing.T) {
+		m := MockFor[func(error) int]()
+		m.When(Nil).Return(1)
+		m.When(NotNil).Return(2)
+		fn, _ := m.Make()
+		gt.Equal(t, fn((*os.PathError)(nil)), 2)
+	})
+	t.Run("not nilable", func(t *testing.T) {
+		defer func() {
+			e := recover()
+			gt.NotNil(t, e)
+		}()
+		m := MockFor[func(int)]()
+		m.When(Nil)
+	})
+}
+
+func TestZero(t *testing.T) {
+	m := MockFor[func(string, error) int]()
+	m.When(Zero, Zero).Return(1)
+	fn, _ := m.Make()
+	gt.Equal(t, fn("", nil), 1)
+	gt.Equal(t, fn("a", nil), 0)
+	gt.Equal(t, fn("", io.EOF), 0)
+
+	t.Run("interface", func(t *testing.T) {
+		m := MockFor[func(any) int]()
+		m.When(Zero).Return(1)
+		fn, _ := m.Make()
+		gt.Equal(t, fn(nil), 1)
+		gt.Equal(t, fn(0), 0)
+		gt.Equal(t, fn(""), 0)
+	})
+}
+
+func TestAnyOfType(t *testing.T) {
+	t.Run("concrete type", func(t *testing.T) {
+		m := MockFor[func(error) int]()
+		m.When(AnyOfType[*os.PathError]()).Return(1)
+		fn, _ := m.Make()
+		gt.Equal(t, fn(&os.PathError{Err: io.EOF}), 1)
+		gt.Equal(t, fn(io.EOF), 0)
+		gt.Equal(t, fn(nil), 0)
+	})
+	t.Run("interface type", func(t *testing.T) {
+		m := MockFor[func(io.Reader) int]()
+		m.When(AnyOfType[io.ReadCloser]()).Return(1)
+		fn, _ := m.Make()
+		gt.Equal(t, fn(io.NopCloser(strings.NewReader(""))), 1)
+		gt.Equal(t, fn(strings.NewReader("")), 0)
+	})
+	t.Run("same type returns the same condition", func(t *testing.T) {
+		m := MockFor[func(any)]()
+		gt.True(t, m.When(AnyOfType[int]()) == m.When(AnyOfType[int]()))
+		gt.True(t, m.When(AnyOfType[int]()) != m.When(AnyOfType[string]()))
+	})
+	t.Run("not assignable", func(t *testing.T) {
+		defer func() {
+			e := recover()
+			gt.NotNil(t, e)
+		}()
+		m := MockFor[func(error)]()
+		m.When(AnyOfType[string]())
+	})
+}
@@ -0,0 +1,134 @@
+package mofu
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+var contextType = reflect.TypeFor[context.Context]()
+
+func checkContextType(m Matcher, typ reflect.Type) error {
+	if !typ.Implements(contextType) {
+		return fmt.Errorf("cannot use %s for %s value", m, typ)
+	}
+	return nil
+}
+
+type contextValueMatcher struct {
+	key any
+	val any
+	m   Matcher
+}
+
+// ContextWithValue returns a [Matcher] that accepts a [context.Context]
+// if val accepts the value associated with key in it.
+//
+// Val is either a value or a [Matcher].
+func ContextWithValue(key, val any) Matcher {
+	return &contextValueMatcher{key: key, val: val}
+}
+
+func (c *contextValueMatcher) Match(v any) bool {
+	if c.m == nil {
+		m, ok := bindDynamic(c, v)
+		return ok && m.Match(v)
+	}
+	ctx, ok := v.(context.Context)
+	if !ok || isNil(ctx) {
+		return false
+	}
+	return c.m.Match(ctx.Value(c.key))
+}
+
+func (c *contextValueMatcher) Equal(o Matcher) bool {
+	p, ok := o.(*contextValueMatcher)
+	return ok && identical(c.key, p.key) && c.m.Equal(p.m)
+}
+
+func (c *contextValueMatcher) String() string {
+	return fmt.Sprintf("contextWithValue(%v, %s)", c.key, c.m)
+}
+
+func (c *contextValueMatcher) bindType(typ reflect.Type, o *options) (Matcher, error) {
+	m, err := newMatcher(c.val, reflect.TypeFor[any](), o)
+	if err != nil {
+		return nil, err
+	}
+	p := &contextValueMatcher{c.key, c.val, m}
+	if err := checkContextType(p, typ); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+var _ Matcher = (*contextValueMatcher)(nil)
+
+type contextDeadlineMatcher time.Duration
+
+// ContextHasDeadline returns a [Matcher] that accepts a [context.Context]
+// if it has a deadline that expires within d from now.
+func ContextHasDeadline(d time.Duration) Matcher {
+	return contextDeadlineMatcher(d)
+}
+
+func (d contextDeadlineMatcher) Match(v any) bool {
+	ctx, ok := v.(context.Context)
+	if !ok || isNil(ctx) {
+		return false
+	}
+	t, ok := ctx.Deadline()
+	return ok && time.Until(t) <= time.Duration(d)
+}
+
+func (d contextDeadlineMatcher) Equal(o Matcher) bool { return o == d }
+
+func (d contextDeadlineMatcher) String() string {
+	return fmt.Sprintf("contextHasDeadline(%v)", time.Duration(d))
+}
+
+func (d contextDeadlineMatcher) bindType(typ reflect.Type, o *options) (Matcher, error) {
+	if err := checkContextType(d, typ); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+var _ Matcher = contextDeadlineMatcher(0)
+
+type contextStateMatcher bool
+
+func (s contextStateMatcher) Match(v any) bool {
+	ctx, ok := v.(context.Context)
+	if !ok || isNil(ctx) {
+		return false
+	}
+	return (ctx.Err() != nil) == bool(s)
+}
+
+func (s contextStateMatcher) Equal(o Matcher) bool { return o == s }
+
+func (s contextStateMatcher) String() string {
+	if s == ContextDone {
+		return "<context done>"
+	}
+	return "<context not done>"
+}
+
+func (s contextStateMatcher) bindType(typ reflect.Type, o *options) (Matcher, error) {
+	if err := checkContextType(s, typ); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+var _ Matcher = contextStateMatcher(false)
+
+const (
+	// ContextDone accepts a [context.Context] that is already canceled or has exceeded its deadline.
+	ContextDone = contextStateMatcher(true)
+
+	// ContextNotDone accepts a [context.Context] that is neither canceled nor exceeded its deadline.
+	ContextNotDone = contextStateMatcher(false)
+)
@@ -0,0 +1,71 @@
+package mofu
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/m-mizutani/gt"
+)
+
+type requestIDKey struct{}
+
+func TestContextWithValue(t *testing.T) {
+	t.Run("value", func(t *testing.T) {
+		m := MockFor[func(context.Context, string) int]()
+		m.When(ContextWithValue(requestIDKey{}, "req-1"), Any).Return(1)
+		m.When(ContextWithValue(requestIDKey{}, HasPrefix("req-")), Any).Return(2)
+		fn, _ := m.Make()
+		ctx := context.Background()
+		gt.Equal(t, fn(context.WithValue(ctx, requestIDKey{}, "req-1"), ""), 1)
+		gt.Equal(t, fn(context.WithValue(ctx, requestIDKey{}, "req-2"), ""), 2)
+		gt.Equal(t, fn(ctx, ""), 0)
+		var nilCtx context.Context
+		gt.Equal(t, fn(nilCtx, ""), 0)
+	})
+	t.Run("same pattern returns the same condition", func(t *testing.T) {
+		m := MockFor[func(context.Context)]()
+		c := m.When(ContextWithValue(requestIDKey{}, "a"))
+		gt.True(t, c == m.When(ContextWithValue(requestIDKey{}, "a")))
+		gt.True(t, c != m.When(ContextWithValue(requestIDKey{}, "b")))
+	})
+	t.Run("not a context", func(t *testing.T) {
+		defer func() {
+			e := recover()
+			gt.NotNil(t, e)
+		}()
+		m := MockFor[func(string)]()
+		m.When(ContextWithValue(requestIDKey{}, "a"))
+	})
+	t.Run("direct call", func(t *testing.T) {
+		ctx := context.WithValue(context.Background(), requestIDKey{}, "req-1")
+		gt.True(t, ContextWithValue(requestIDKey{}, "req-1").Match(ctx))
+		gt.False(t, ContextWithValue(requestIDKey{}, "req-2").Match(ctx))
+		gt.False(t, ContextWithValue(requestIDKey{}, "req-1").Match(nil))
+	})
+}
+
+func TestContextHasDeadline(t *testing.T) {
+	m := MockFor[func(context.Context) int]()
+	m.When(ContextHasDeadline(time.Minute)).Return(1)
+	fn, _ := m.Make()
+	ctx := context.Background()
+	ctx1, cancel1 := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel1()
+	gt.Equal(t, fn(ctx1), 1)
+	ctx2, cancel2 := context.WithTimeout(ctx, time.Hour)
+	defer cancel2()
+	gt.Equal(t, fn(ctx2), 0)
+	gt.Equal(t, fn(ctx), 0)
+}
+
+func TestContextDone(t *testing.T) {
+	m := MockFor[func(context.Context) int]()
+	m.When(ContextDone).Return(1)
+	m.When(ContextNotDone).Return(2)
+	fn, _ := m.Make()
+	ctx, cancel := context.WithCancel(context.Background())
+	gt.Equal(t, fn(ctx), 2)
+	cancel()
+	gt.Equal(t, fn(ctx), 1)
+}
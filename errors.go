@@ -0,0 +1,106 @@
+package mofu
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+var errorType = reflect.TypeFor[error]()
+
+type errorMatcher struct {
+	name string
+	arg  any
+	fn   func(err error) bool
+}
+
+// ErrorIs returns a [Matcher] that accepts an error if [errors.Is] reports it matches target.
+// An error holding a nil pointer is accepted only if it is identical to target.
+func ErrorIs(target error) Matcher {
+	return &errorMatcher{"errorIs", target, func(err error) bool {
+		return errors.Is(err, target)
+	}}
+}
+
+// ErrorContains returns a [Matcher] that accepts a non-nil error if its message contains substr.
+func ErrorContains(substr string) Matcher {
+	return &errorMatcher{"errorContains", substr, func(err error) bool {
+		return err != nil && strings.Contains(err.Error(), substr)
+	}}
+}
+
+func (m *errorMatcher) Match(v any) bool {
+	err, _ := v.(error)
+	if err != nil && isNil(err) {
+		// Methods of a typed nil such as Error and Unwrap may panic.
+		return m.name == "errorIs" && identical(err, m.arg)
+	}
+	return m.fn(err)
+}
+
+func (m *errorMatcher) Equal(o Matcher) bool {
+	p, ok := o.(*errorMatcher)
+	return ok && m.name == p.name && identical(m.arg, p.arg)
+}
+
+func (m *errorMatcher) String() string {
+	if s, ok := m.arg.(string); ok {
+		return fmt.Sprintf("%s(%q)", m.name, s)
+	}
+	return fmt.Sprintf("%s(%v)", m.name, m.arg)
+}
+
+func (m *errorMatcher) bindType(typ reflect.Type, o *options) (Matcher, error) {
+	if !typ.Implements(errorType) {
+		return nil, fmt.Errorf("cannot use %s for %s value", m.name, typ)
+	}
+	return m, nil
+}
+
+var _ Matcher = (*errorMatcher)(nil)
+
+// identical reports whether a and b are the same value.
+// Unlike reflect.DeepEqual, it does not look into pointers.
+func identical(a, b any) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	t := reflect.TypeOf(a)
+	return t == reflect.TypeOf(b) && t.Comparable() && a == b
+}
+
+type errorAsMatcher[E error] struct{}
+
+// ErrorAs returns a [Matcher] that accepts an error if [errors.As] finds an error of type E in it.
+// It does not accept a nil error, including a nil pointer of type E.
+func ErrorAs[E error]() Matcher {
+	return errorAsMatcher[E]{}
+}
+
+func (errorAsMatcher[E]) Match(v any) bool {
+	err, _ := v.(error)
+	if isNil(err) {
+		return false
+	}
+	var target E
+	return errors.As(err, &target)
+}
+
+func (errorAsMatcher[E]) Equal(o Matcher) bool {
+	_, ok := o.(errorAsMatcher[E])
+	return ok
+}
+
+func (errorAsMatcher[E]) String() string {
+	return fmt.Sprintf("errorAs(%s)", reflect.TypeFor[E]())
+}
+
+func (m errorAsMatcher[E]) bindType(typ reflect.Type, o *options) (Matcher, error) {
+	if !typ.Implements(errorType) {
+		return nil, fmt.Errorf("cannot use %s for %s value", m, typ)
+	}
+	return m, nil
+}
+
+var _ Matcher = errorAsMatcher[error]{}
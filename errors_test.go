@@ -0,0 +1,75 @@
+package mofu
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"testing"
+
+	"github.com/m-mizutani/gt"
+)
+
+func TestErrorIs(t *testing.T) {
+	t.Run("wrapped error", func(t *testing.T) {
+		m := MockFor[func(context.Context, error) int]()
+		m.When(Any, ErrorIs(fs.ErrNotExist)).Return(1)
+		fn, _ := m.Make()
+		ctx := context.Background()
+		gt.Equal(t, fn(ctx, fmt.Errorf("open: %w", fs.ErrNotExist)), 1)
+		gt.Equal(t, fn(ctx, io.EOF), 0)
+		gt.Equal(t, fn(ctx, nil), 0)
+	})
+	t.Run("distinct errors with the same message", func(t *testing.T) {
+		m := MockFor[func(error)]()
+		err1 := errors.New("x")
+		err2 := errors.New("x")
+		gt.True(t, m.When(ErrorIs(err1)) == m.When(ErrorIs(err1)))
+		gt.True(t, m.When(ErrorIs(err1)) != m.When(ErrorIs(err2)))
+	})
+	t.Run("not an error", func(t *testing.T) {
+		defer func() {
+			e := recover()
+			gt.NotNil(t, e)
+		}()
+		m := MockFor[func(string)]()
+		m.When(ErrorIs(io.EOF))
+	})
+	t.Run("typed nil", func(t *testing.T) {
+		var perr *os.PathError
+		m := MockFor[func(error) int]()
+		m.When(ErrorIs(io.EOF)).Return(1)
+		m.When(ErrorIs(perr)).Return(2)
+		fn, _ := m.Make()
+		gt.Equal(t, fn(perr), 2)
+		gt.Equal(t, fn(nil), 0)
+	})
+}
+
+func TestErrorAs(t *testing.T) {
+	m := MockFor[func(error) int]()
+	m.When(ErrorAs[*os.PathError]()).Return(1)
+	fn, _ := m.Make()
+	gt.Equal(t, fn(fmt.Errorf("load: %w", &os.PathError{Op: "open", Err: io.EOF})), 1)
+	gt.Equal(t, fn(io.EOF), 0)
+	gt.Equal(t, fn(nil), 0)
+	gt.Equal(t, fn((*os.PathError)(nil)), 0)
+}
+
+func TestErrorContains(t *testing.T) {
+	m := MockFor[func(error) int]()
+	m.When(ErrorContains("timeout")).Return(1)
+	fn, _ := m.Make()
+	gt.Equal(t, fn(errors.New("i/o timeout")), 1)
+	gt.Equal(t, fn(io.EOF), 0)
+	gt.Equal(t, fn(nil), 0)
+}
+
+func TestErrorMatcher_typedNil(t *testing.T) {
+	m := MockFor[func(*os.PathError) int]()
+	m.When(ErrorContains("timeout")).Return(1)
+	fn, _ := m.Make()
+	gt.Equal(t, fn(nil), 0)
+}
@@ -0,0 +1,196 @@
+package mofu
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// expectation is the expected number of calls for a condition.
+type expectation struct {
+	min int
+	max int // unlimited if max < 0
+}
+
+func (e *expectation) isSatisfied(n int) bool {
+	return n >= e.min && (e.max < 0 || n <= e.max)
+}
+
+func (e *expectation) String() string {
+	switch {
+	case e.max == 0:
+		return "never"
+	case e.min == e.max:
+		return "exactly " + times(e.min)
+	case e.max < 0:
+		return "at least " + times(e.min)
+	case e.min == 0:
+		return "at most " + times(e.max)
+	default:
+		return fmt.Sprintf("between %d and %s", e.min, times(e.max))
+	}
+}
+
+func times(n int) string {
+	if n == 1 {
+		return "1 time"
+	}
+	return fmt.Sprintf("%d times", n)
+}
+
+func (c *Cond[T]) expectation() expectation {
+	if c.expect == nil {
+		return expectation{min: 0, max: -1}
+	}
+	return *c.expect
+}
+
+// setExpectation updates the expectation of c to e.
+// It reports an error through the mock if n, the number given by the caller, is negative or e is contradictory.
+func (c *Cond[T]) setExpectation(n int, e expectation) {
+	switch {
+	case n < 0:
+		c.m.fail(fmt.Errorf("%s: negative number of calls: %d", c, n))
+	case e.max >= 0 && e.min > e.max:
+		c.m.fail(fmt.Errorf("%s: contradictory expectation: at least %s and at most %s", c, times(e.min), times(e.max)))
+	default:
+		c.expect = &e
+	}
+}
+
+// Times declares that c should be used exactly n times.
+// The expectation is checked by [Recorder.Verify].
+// It panics, or calls t.Fatal under [WithT], if n is negative.
+func (c *Cond[T]) Times(n int) *Cond[T] {
+	c.setExpectation(n, expectation{min: n, max: n})
+	return c
+}
+
+// AtLeast declares that c should be used n times or more.
+// The expectation is checked by [Recorder.Verify].
+// It panics, or calls t.Fatal under [WithT], if n is negative or greater than the upper limit declared before.
+func (c *Cond[T]) AtLeast(n int) *Cond[T] {
+	e := c.expectation()
+	e.min = n
+	c.setExpectation(n, e)
+	return c
+}
+
+// AtMost declares that c should be used n times or less.
+// The expectation is checked by [Recorder.Verify].
+// It panics, or calls t.Fatal under [WithT], if n is negative or less than the lower limit declared before.
+func (c *Cond[T]) AtMost(n int) *Cond[T] {
+	e := c.expectation()
+	e.max = n
+	c.setExpectation(n, e)
+	return c
+}
+
+// Never declares that c should not be used.
+// The expectation is checked by [Recorder.Verify].
+func (c *Cond[T]) Never() *Cond[T] {
+	return c.Times(0)
+}
+
+// Times is like [Cond.Times] except this declares for the default condition.
+func (m *Mock[T]) Times(n int) *Mock[T] {
+	m.dflt.Times(n)
+	return m
+}
+
+// AtLeast is like [Cond.AtLeast] except this declares for the default condition.
+func (m *Mock[T]) AtLeast(n int) *Mock[T] {
+	m.dflt.AtLeast(n)
+	return m
+}
+
+// AtMost is like [Cond.AtMost] except this declares for the default condition.
+func (m *Mock[T]) AtMost(n int) *Mock[T] {
+	m.dflt.AtMost(n)
+	return m
+}
+
+// Never is like [Cond.Never] except this declares for the default condition.
+func (m *Mock[T]) Never() *Mock[T] {
+	m.dflt.Never()
+	return m
+}
+
+// exceeds reports whether the number of calls of c exceeds its upper limit in r at first time.
+// It always returns false unless the mock is created with [WithT].
+// The caller must hold the lock.
+func (r *Recorder[T]) exceeds(c *Cond[T]) bool {
+	if r.m.opts.t == nil || c.expect == nil || c.expect.max < 0 {
+		return false
+	}
+	if r.nused[c] <= c.expect.max || r.exceeded[c] {
+		return false
+	}
+	if r.exceeded == nil {
+		r.exceeded = make(map[*Cond[T]]bool)
+	}
+	r.exceeded[c] = true
+	return true
+}
+
+// Verify checks whether all the conditions of the mock satisfy their expectations,
+// and whether all the values added by ReturnOnce, ReturnOnceFunc and PanicOnce are consumed.
+// Conditions without expectations are also reported if they have never matched.
+// It reports each violation to t with the pattern of the condition.
+//
+// If [Mock.Make] is called more than once, expectations are checked for each recorder,
+// while unmatched conditions and unconsumed values are checked over all functions made by the mock,
+// and only reported by the recorder returned first.
+func (r *Recorder[T]) Verify(t testing.TB) {
+	t.Helper()
+	for _, err := range r.verify() {
+		t.Error(err)
+	}
+}
+
+func (r *Recorder[T]) verify() []error {
+	var used map[*Cond[T]]int
+	if r.m.recs[0] == r {
+		used = r.m.maxUsed()
+	}
+	r.RLock()
+	defer r.RUnlock()
+	var errs []error
+	for _, c := range slices.Concat(r.m.conds, []*Cond[T]{r.m.dflt}) {
+		n := r.nused[c]
+		if c.expect != nil && !c.expect.isSatisfied(n) && !r.exceeded[c] {
+			errs = append(errs, fmt.Errorf("%s: called %s; want %s", c, times(n), c.expect))
+		}
+		if used == nil {
+			continue
+		}
+		var a []string
+		if c != r.m.dflt && c.expect == nil && used[c] == 0 {
+			a = append(a, "never matched")
+		}
+		switch k := len(c.evalq) - used[c]; {
+		case k == 1:
+			a = append(a, "1 queued value is not consumed")
+		case k > 1:
+			a = append(a, fmt.Sprintf("%d queued values are not consumed", k))
+		}
+		if len(a) > 0 {
+			errs = append(errs, fmt.Errorf("%s: %s", c, strings.Join(a, ", ")))
+		}
+	}
+	return errs
+}
+
+// maxUsed returns the largest number of calls of each condition among the recorders of m.
+func (m *Mock[T]) maxUsed() map[*Cond[T]]int {
+	used := make(map[*Cond[T]]int)
+	for _, r := range m.recs {
+		r.RLock()
+		for c, n := range r.nused {
+			used[c] = max(used[c], n)
+		}
+		r.RUnlock()
+	}
+	return used
+}
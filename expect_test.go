@@ -0,0 +1,259 @@
+package mofu
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/m-mizutani/gt"
+)
+
+// fakeTB records failures instead of failing the test.
+type fakeTB struct {
+	testing.TB
+	errs     []string
+	fatal    bool
+	cleanups []func()
+}
+
+func (t *fakeTB) Fatal(args ...any) {
+	t.fatal = true
+	t.Error(args...)
+}
+
+func (t *fakeTB) Cleanup(f func()) {
+	t.cleanups = append(t.cleanups, f)
+}
+
+// finish runs cleanup functions like the end of a test.
+func (t *fakeTB) finish() {
+	for _, f := range slices.Backward(t.cleanups) {
+		f()
+	}
+}
+
+func (t *fakeTB) Helper() {}
+
+func (t *fakeTB) Error(args ...any) {
+	t.errs = append(t.errs, fmt.Sprint(args...))
+}
+
+func (t *fakeTB) Errorf(format string, args ...any) {
+	t.errs = append(t.errs, fmt.Sprintf(format, args...))
+}
+
+func TestRecorder_Verify(t *testing.T) {
+	t.Run("satisfied", func(t *testing.T) {
+		m := MockFor[func(string) int]()
+		m.When("foo").Return(1).Times(2)
+		m.When("bar").AtLeast(1).AtMost(2)
+		m.When("baz").Never()
+		m.AtMost(1)
+		fn, r := m.Make()
+		fn("foo")
+		fn("bar")
+		fn("foo")
+		fn("qux")
+		var tb fakeTB
+		r.Verify(&tb)
+		gt.A(t, tb.errs).Length(0)
+	})
+	t.Run("violated", func(t *testing.T) {
+		m := MockFor[func(string) int]()
+		m.When("foo").Times(1)
+		m.When("bar").AtLeast(1)
+		m.When("baz").Never()
+		m.When(Any).AtLeast(1).AtMost(2).Priority(-1)
+		m.Times(0)
+		fn, r := m.Make()
+		fn("foo")
+		fn("foo")
+		fn("baz")
+		var tb fakeTB
+		r.Verify(&tb)
+		gt.Equal(t, tb.errs, []string{
+			`When("foo"): called 2 times; want exactly 1 time`,
+			`When("bar"): called 0 times; want at least 1 time`,
+			`When("baz"): called 1 time; want never`,
+			`When(<any>): called 0 times; want between 1 and 2 times`,
+		})
+	})
+	t.Run("default", func(t *testing.T) {
+		m := MockFor[func(string) int]()
+		m.AtLeast(1)
+		_, r := m.Make()
+		var tb fakeTB
+		r.Verify(&tb)
+		gt.Equal(t, tb.errs, []string{"default: called 0 times; want at least 1 time"})
+	})
+}
+
+func TestCond_Times_invalid(t *testing.T) {
+	tests := map[string]func(c *Cond[func(string)]){
+		"negative times":       func(c *Cond[func(string)]) { c.Times(-1) },
+		"negative at least":    func(c *Cond[func(string)]) { c.AtLeast(-1) },
+		"never and at least":   func(c *Cond[func(string)]) { c.Never().AtLeast(1) },
+		"at least and at most": func(c *Cond[func(string)]) { c.AtLeast(2).AtMost(1) },
+	}
+	for name, f := range tests {
+		t.Run(name, func(t *testing.T) {
+			var tb fakeTB
+			m := New[func(string)](&tb)
+			f(m.When("a"))
+			gt.True(t, tb.fatal)
+		})
+	}
+	t.Run("panic", func(t *testing.T) {
+		defer func() {
+			e := recover()
+			gt.String(t, fmt.Sprint(e)).Equal(`When("a"): contradictory expectation: at least 2 times and at most 1 time`)
+		}()
+		m := MockFor[func(string)]()
+		m.When("a").AtMost(1).AtLeast(2)
+	})
+}
+
+func TestRecorder_Verify_unused(t *testing.T) {
+	t.Run("queue", func(t *testing.T) {
+		m := MockFor[func(string) int]()
+		m.When("foo").ReturnOnce(1).ReturnOnce(2).ReturnOnce(3)
+		m.ReturnOnce(0)
+		fn, r := m.Make()
+		fn("foo")
+		var tb fakeTB
+		r.Verify(&tb)
+		gt.Equal(t, tb.errs, []string{
+			`When("foo"): 2 queued values are not consumed`,
+			`default: 1 queued value is not consumed`,
+		})
+	})
+	t.Run("condition", func(t *testing.T) {
+		m := MockFor[func(string) int]()
+		m.When("foo").Return(1)
+		m.When("bar").ReturnOnce(1)
+		m.When("baz").Never()
+		fn, r := m.Make()
+		fn("qux")
+		var tb fakeTB
+		r.Verify(&tb)
+		gt.Equal(t, tb.errs, []string{
+			`When("foo"): never matched`,
+			`When("bar"): never matched, 1 queued value is not consumed`,
+		})
+	})
+	t.Run("cleanup", func(t *testing.T) {
+		var tb fakeTB
+		m := New[func(string) int](&tb)
+		m.When("foo").ReturnOnce(1)
+		fn, _ := m.Make()
+		fn("foo")
+		m.When("bar").Return(2)
+		tb.finish()
+		gt.Equal(t, tb.errs, []string{`When("bar"): never matched`})
+	})
+	t.Run("multiple functions", func(t *testing.T) {
+		var tb fakeTB
+		m := New[func(string) int](&tb)
+		m.When("a").ReturnOnce(1)
+		m.When("b").Return(2)
+		m.When("c").Return(3)
+		f1, _ := m.Make()
+		f2, _ := m.Make()
+		f1("a")
+		f2("b")
+		tb.finish()
+		gt.Equal(t, tb.errs, []string{`When("c"): never matched`})
+	})
+}
+
+func TestWithT(t *testing.T) {
+	t.Run("verify on cleanup", func(t *testing.T) {
+		var tb fakeTB
+		m := New[func(string) int](&tb)
+		m.When("foo").Return(1).AtLeast(1)
+		fn, _ := m.Make()
+		fn("bar")
+		gt.A(t, tb.errs).Length(0)
+		tb.finish()
+		gt.Equal(t, tb.errs, []string{`When("foo"): called 0 times; want at least 1 time`})
+	})
+	t.Run("unexpected call", func(t *testing.T) {
+		var tb fakeTB
+		m := MockFor[func(string, int) int](WithT(&tb))
+		m.When("foo", Any).AtMost(1)
+		fn, _ := m.Make()
+		fn("foo", 1)
+		fn("foo", 2)
+		fn("foo", 3)
+		gt.Equal(t, tb.errs, []string{`When("foo", <any>): unexpected call with ("foo", 2); want at most 1 time`})
+		tb.finish()
+		gt.A(t, tb.errs).Length(1)
+	})
+	t.Run("configuration error", func(t *testing.T) {
+		var tb fakeTB
+		m := New[func(string) int](&tb)
+		m.When(1)
+		gt.True(t, tb.fatal)
+		gt.Equal(t, tb.errs, []string{"mismatched types string and int"})
+	})
+	t.Run("return twice", func(t *testing.T) {
+		var tb fakeTB
+		m := New[func() int](&tb)
+		m.Return(1).Return(2)
+		gt.True(t, tb.fatal)
+		gt.A(t, tb.errs).Length(1)
+	})
+}
+
+func TestWithStrict(t *testing.T) {
+	t.Run("report through t", func(t *testing.T) {
+		var tb fakeTB
+		m := New[func(string, int) int](&tb, WithStrict())
+		m.When("key_1", 1).Return(1)
+		m.When(HasPrefix("x"), Any).Return(2)
+		fn, r := m.Make()
+		gt.Equal(t, fn("key_1", 1), 1)
+		gt.A(t, tb.errs).Length(0)
+		gt.Equal(t, fn("key-1", 1), 0)
+		gt.Equal(t, r.Count(), 2)
+		gt.Equal(t, tb.errs, []string{`unexpected call with ("key-1", 1)
+registered conditions:
+	When("key_1", 1)
+	When(hasPrefix("x"), <any>)
+closest condition: When("key_1", 1)
+	args[0]: got "key-1", want "key_1"`})
+	})
+	t.Run("panic", func(t *testing.T) {
+		m := MockFor[func(string) int](WithStrict())
+		m.When("a").Return(1)
+		fn, r := m.Make()
+		func() {
+			defer func() {
+				e := recover()
+				gt.NotNil(t, e)
+			}()
+			fn("b")
+		}()
+		_, ok := r.Call(0).PanicValue()
+		gt.True(t, ok)
+	})
+	t.Run("no conditions", func(t *testing.T) {
+		var tb fakeTB
+		m := New[func(string) int](&tb, WithStrict())
+		fn, _ := m.Make()
+		fn("a")
+		gt.Equal(t, tb.errs, []string{"unexpected call with (\"a\")\nno conditions are registered"})
+	})
+	t.Run("variadic arguments", func(t *testing.T) {
+		var tb fakeTB
+		m := New[func(string, ...int)](&tb, WithStrict())
+		m.When("a", Len(2))
+		fn, _ := m.Make()
+		fn("a", 1)
+		gt.Equal(t, tb.errs, []string{`unexpected call with ("a", 1)
+registered conditions:
+	When("a", len(2)...)
+closest condition: When("a", len(2)...)
+	args[1:]: got (1), want len(2)...`})
+	})
+}
@@ -0,0 +1,149 @@
+package mofu
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+type fieldsMatcher struct {
+	fields map[string]any
+	typ    reflect.Type // struct type
+	ms     []*fieldMatcher
+}
+
+type fieldMatcher struct {
+	name  string
+	index []int
+	m     Matcher
+}
+
+// Fields returns a [Matcher] that accepts a struct, or a pointer to a struct,
+// if each field named in fields is accepted.
+//
+// Each value of fields is either a value or a [Matcher].
+func Fields(fields map[string]any) Matcher {
+	return &fieldsMatcher{fields: fields}
+}
+
+// Partial returns a [Matcher] that accepts a struct, or a pointer to a struct,
+// if its fields equal the exported non-zero fields of x.
+// A non-zero field of a struct, or a pointer to a struct, is compared with Partial recursively.
+//
+// X must be a struct or a pointer to a struct.
+func Partial(x any) Matcher {
+	v := reflect.ValueOf(x)
+	for v.Kind() == reflect.Pointer && !v.IsNil() {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("%T is not a struct", x))
+	}
+	fields := make(map[string]any)
+	t := v.Type()
+	for i := range t.NumField() {
+		f := t.Field(i)
+		if !f.IsExported() || v.Field(i).IsZero() {
+			continue
+		}
+		if hasExportedFields(f.Type) {
+			fields[f.Name] = Partial(v.Field(i).Interface())
+		} else {
+			fields[f.Name] = v.Field(i).Interface()
+		}
+	}
+	return Fields(fields)
+}
+
+// hasExportedFields reports whether typ is a struct, or a pointer to a struct, that has exported fields.
+// A struct without exported fields, such as time.Time, is compared as a value.
+func hasExportedFields(typ reflect.Type) bool {
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+	if typ.Kind() != reflect.Struct {
+		return false
+	}
+	for i := range typ.NumField() {
+		if typ.Field(i).IsExported() {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *fieldsMatcher) Match(v any) bool {
+	if s.typ == nil {
+		m, ok := bindDynamic(s, v)
+		return ok && m.Match(v)
+	}
+	p := reflect.ValueOf(v)
+	for p.Kind() == reflect.Pointer {
+		if p.IsNil() {
+			return false
+		}
+		p = p.Elem()
+	}
+	if !p.IsValid() || p.Type() != s.typ {
+		return false
+	}
+	for _, f := range s.ms {
+		fv, err := p.FieldByIndexErr(f.index)
+		if err != nil {
+			return false
+		}
+		if !f.m.Match(fv.Interface()) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *fieldsMatcher) Equal(o Matcher) bool {
+	p, ok := o.(*fieldsMatcher)
+	if !ok || s.typ != p.typ || len(s.ms) != len(p.ms) {
+		return false
+	}
+	for i, f := range s.ms {
+		if f.name != p.ms[i].name || !f.m.Equal(p.ms[i].m) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *fieldsMatcher) String() string {
+	a := make([]string, len(s.ms))
+	for i, f := range s.ms {
+		a[i] = fmt.Sprintf("%s: %s", f.name, f.m)
+	}
+	return fmt.Sprintf("fields(%s)", strings.Join(a, ", "))
+}
+
+func (s *fieldsMatcher) bindType(typ reflect.Type, o *options) (Matcher, error) {
+	t := typ
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("cannot use fields for %s value", typ)
+	}
+	names := slices.Sorted(maps.Keys(s.fields))
+	ms := make([]*fieldMatcher, len(names))
+	for i, name := range names {
+		f, ok := t.FieldByName(name)
+		if !ok || !f.IsExported() {
+			return nil, fmt.Errorf("%s has no exported field %s", t, name)
+		}
+		m, err := newMatcher(s.fields[name], f.Type, o)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", name, err)
+		}
+		ms[i] = &fieldMatcher{name, f.Index, m}
+	}
+	return &fieldsMatcher{s.fields, t, ms}, nil
+}
+
+var _ Matcher = (*fieldsMatcher)(nil)
@@ -0,0 +1,114 @@
+package mofu
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/m-mizutani/gt"
+)
+
+type address struct {
+	City string
+	Zip  string
+}
+
+type createUserRequest struct {
+	ID        string
+	Name      string
+	Age       int
+	Addr      *address
+	CreatedAt time.Time
+
+	secret string
+}
+
+func TestFields(t *testing.T) {
+	t.Run("pointer", func(t *testing.T) {
+		m := MockFor[func(context.Context, *createUserRequest) int]()
+		m.When(Any, Fields(map[string]any{
+			"Name": "alice",
+			"Age":  Not(0),
+		})).Return(1)
+		fn, _ := m.Make()
+		ctx := context.Background()
+		gt.Equal(t, fn(ctx, &createUserRequest{ID: "1", Name: "alice", Age: 20}), 1)
+		gt.Equal(t, fn(ctx, &createUserRequest{ID: "2", Name: "alice"}), 0)
+		gt.Equal(t, fn(ctx, &createUserRequest{ID: "3", Name: "bob", Age: 20}), 0)
+		gt.Equal(t, fn(ctx, nil), 0)
+	})
+	t.Run("nested", func(t *testing.T) {
+		m := MockFor[func(createUserRequest) int]()
+		m.When(Fields(map[string]any{
+			"Addr": Fields(map[string]any{"City": "Tokyo"}),
+		})).Return(1)
+		fn, _ := m.Make()
+		gt.Equal(t, fn(createUserRequest{Addr: &address{City: "Tokyo"}}), 1)
+		gt.Equal(t, fn(createUserRequest{Addr: &address{City: "Osaka"}}), 0)
+		gt.Equal(t, fn(createUserRequest{}), 0)
+	})
+	t.Run("same fields returns the same condition", func(t *testing.T) {
+		m := MockFor[func(*createUserRequest)]()
+		c1 := m.When(Fields(map[string]any{"Name": "alice", "Age": 1}))
+		c2 := m.When(Fields(map[string]any{"Age": 1, "Name": "alice"}))
+		gt.True(t, c1 == c2)
+		gt.String(t, c1.pattern[0].String()).Equal(`fields(Age: 1, Name: "alice")`)
+	})
+	t.Run("unknown field", func(t *testing.T) {
+		defer func() {
+			e := recover()
+			gt.NotNil(t, e)
+		}()
+		m := MockFor[func(*createUserRequest)]()
+		m.When(Fields(map[string]any{"Email": ""}))
+	})
+	t.Run("unexported field", func(t *testing.T) {
+		defer func() {
+			e := recover()
+			gt.NotNil(t, e)
+		}()
+		m := MockFor[func(*createUserRequest)]()
+		m.When(Fields(map[string]any{"secret": ""}))
+	})
+	t.Run("mismatched field type", func(t *testing.T) {
+		defer func() {
+			e := recover()
+			gt.NotNil(t, e)
+		}()
+		m := MockFor[func(*createUserRequest)]()
+		m.When(Fields(map[string]any{"Age": "1"}))
+	})
+}
+
+func TestPartial(t *testing.T) {
+	m := MockFor[func(*createUserRequest) int]()
+	m.When(Partial(&createUserRequest{Name: "alice", secret: "x"})).Return(1)
+	fn, _ := m.Make()
+	gt.Equal(t, fn(&createUserRequest{ID: "1", Name: "alice", CreatedAt: time.Now()}), 1)
+	gt.Equal(t, fn(&createUserRequest{ID: "1", Name: "bob"}), 0)
+
+	t.Run("nested", func(t *testing.T) {
+		m := MockFor[func(*createUserRequest) int]()
+		m.When(Partial(&createUserRequest{Addr: &address{City: "x"}})).Return(1)
+		fn, _ := m.Make()
+		gt.Equal(t, fn(&createUserRequest{Name: "n", Addr: &address{City: "x", Zip: "1"}}), 1)
+		gt.Equal(t, fn(&createUserRequest{Name: "n", Addr: &address{City: "y", Zip: "1"}}), 0)
+		gt.Equal(t, fn(&createUserRequest{Name: "n"}), 0)
+	})
+	t.Run("struct without exported fields", func(t *testing.T) {
+		now := time.Now()
+		m := MockFor[func(*createUserRequest) int]()
+		m.When(Partial(&createUserRequest{CreatedAt: now})).Return(1)
+		fn, _ := m.Make()
+		gt.Equal(t, fn(&createUserRequest{CreatedAt: now}), 1)
+		gt.Equal(t, fn(&createUserRequest{CreatedAt: now.Add(time.Second)}), 0)
+	})
+}
+
+func TestFieldsMatcher_Match(t *testing.T) {
+	r := &createUserRequest{Name: "alice", Addr: &address{City: "x", Zip: "1"}}
+	gt.True(t, Fields(map[string]any{"Name": HasPrefix("a")}).Match(r))
+	gt.True(t, Partial(&createUserRequest{Addr: &address{City: "x"}}).Match(*r))
+	gt.False(t, Partial(createUserRequest{Name: "bob"}).Match(r))
+	gt.False(t, Fields(map[string]any{"Name": "alice"}).Match(nil))
+}
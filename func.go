@@ -1,24 +1,29 @@
 package mofu
 
 import (
+	"errors"
 	"fmt"
-	"iter"
 	"reflect"
+	"runtime"
 	"slices"
-	"sync"
+	"strings"
+	"testing"
+	"time"
 )
 
 // Mock is a mock object for creating a mock function.
 type Mock[T any] struct {
 	fn   reflect.Type
 	name string
+	opts options
 
 	conds []*Cond[T]
 	dflt  *Cond[T]
+	recs  []*Recorder[T] // recorders returned by Make
 }
 
 // MockFor creates an empty mock object.
-func MockFor[T any]() *Mock[T] {
+func MockFor[T any](opts ...Option) *Mock[T] {
 	t := reflect.TypeFor[T]()
 	if t.Kind() != reflect.Func {
 		panic("fn must be a function")
@@ -27,26 +32,36 @@ func MockFor[T any]() *Mock[T] {
 	if t.NumMethod() == 1 {
 		name = t.Method(0).Name
 	}
-	return createMock[T](t, name)
+	return createMock[T](t, name, opts)
+}
+
+// New creates an empty mock object for the test t.
+// It is a shorthand for MockFor[T](WithT(t), opts...).
+func New[T any](t testing.TB, opts ...Option) *Mock[T] {
+	t.Helper()
+	return MockFor[T](append([]Option{WithT(t)}, opts...)...)
 }
 
 // MockOf creates an empty mock object.
 //
 // Fn is only used to specify the type of a mock function.
-func MockOf[T any](fn T) *Mock[T] {
+func MockOf[T any](fn T, opts ...Option) *Mock[T] {
 	v := reflect.ValueOf(fn)
 	t := v.Type()
 	if t.Kind() != reflect.Func {
 		panic("fn must be a function")
 	}
-	return createMock[T](t, funcName(v))
+	return createMock[T](t, funcName(v), opts)
 }
 
-func createMock[T any](t reflect.Type, name string) *Mock[T] {
+func createMock[T any](t reflect.Type, name string, opts []Option) *Mock[T] {
 	m := &Mock[T]{
 		fn:   t,
 		name: name,
 	}
+	for _, opt := range opts {
+		opt(&m.opts)
+	}
 	m.dflt = &Cond[T]{
 		m: m,
 	}
@@ -55,15 +70,12 @@ func createMock[T any](t reflect.Type, name string) *Mock[T] {
 
 // Cond represents a condition for returning values identified by the arguments.
 type Cond[T any] struct {
-	m       *Mock[T]
-	pattern []condExpr
-	evalq   []evaluator
-	dflt    evaluator
-}
-
-type condExpr interface {
-	canAccept(arg *typeval) bool
-	equal(o condExpr) bool
+	m        *Mock[T]
+	pattern  []Matcher
+	evalq    []evaluator
+	dflt     evaluator
+	priority int
+	expect   *expectation
 }
 
 type evaluator interface {
@@ -99,23 +111,68 @@ func (f evalFunc[T]) Eval(args []reflect.Value) []reflect.Value {
 
 // isCorrect reports whether args equals the expected argument pattern of c.
 //
-// The caller should guarantee the length of args equal to the length of args of T.
+// If T is variadic, args should be flattened by flattenVariadic.
 func (c *Cond[T]) isCorrect(args []*typeval) bool {
-	for i, m := range c.pattern {
-		if !m.canAccept(args[i]) {
+	pattern, tail := c.splitPattern()
+	if !c.acceptsLen(len(args)) {
+		return false
+	}
+	if tail != nil && !tail.matchArgs(args[len(pattern):]) {
+		return false
+	}
+	for i, m := range pattern {
+		if !m.Match(args[i].val.Interface()) {
 			return false
 		}
 	}
 	return true
 }
 
-func (c *Cond[T]) equalPattern(pattern []condExpr) bool {
+// splitPattern returns the pattern for fixed arguments and the matcher for variadic arguments.
+// If c does not have a matcher for variadic arguments, it returns the entire pattern and nil.
+func (c *Cond[T]) splitPattern() ([]Matcher, *variadicTail) {
+	return splitTail(c.pattern)
+}
+
+// acceptsLen reports whether the pattern of c can accept n arguments.
+func (c *Cond[T]) acceptsLen(n int) bool {
+	pattern, tail := c.splitPattern()
+	if tail != nil {
+		return n >= len(pattern)
+	}
+	return n == len(pattern)
+}
+
+// wildcards returns the number of wildcard matchers in the pattern of c.
+func (c *Cond[T]) wildcards() int {
+	n := 0
+	for _, m := range c.pattern {
+		if isWildcard(m) {
+			n++
+		}
+	}
+	return n
+}
+
+// String returns the description of c.
+func (c *Cond[T]) String() string {
+	if c == c.m.dflt {
+		return "default"
+	}
+	return fmt.Sprintf("When(%s)", joinMatchers(c.pattern))
+}
+
+// capture stores args to the [Capture] in the pattern of c.
+func (c *Cond[T]) capture(args []*typeval) {
 	for i, m := range c.pattern {
-		if !m.equal(pattern[i]) {
-			return false
+		if p, ok := m.(capturer); ok {
+			p.capture(args[i].val.Interface())
 		}
 	}
-	return true
+}
+
+func (c *Cond[T]) equalPattern(pattern []Matcher) bool {
+	return equalMatchers(c.pattern, pattern)
 }
 
 // checkTypeval checks whether v is assignable to type typ.
@@ -130,7 +187,7 @@ func checkTypeval(v any, typ reflect.Type) (*typeval, error) {
 		if !val.Type().Implements(typ) {
 			return nil, fmt.Errorf("%s does not implement %s", val.Type(), typ)
 		}
-		return &typeval{typ, val}, nil
+		return &typeval{typ: typ, val: val}, nil
 	case reflect.Pointer, reflect.Slice, reflect.Map, reflect.Chan, reflect.Func:
 		if v == nil {
 			return newTypeval(reflect.Zero(typ)), nil
@@ -139,7 +196,7 @@ func checkTypeval(v any, typ reflect.Type) (*typeval, error) {
 		if t := val.Type(); t != typ {
 			return nil, fmt.Errorf("mismatched types %s and %s", typ, t)
 		}
-		return &typeval{typ, val}, nil
+		return &typeval{typ: typ, val: val}, nil
 	default:
 		if v == nil {
 			return nil, fmt.Errorf("cannot use nil as %s value", typ)
@@ -148,7 +205,7 @@ func checkTypeval(v any, typ reflect.Type) (*typeval, error) {
 		if t := val.Type(); t != typ {
 			return nil, fmt.Errorf("mismatched types %s and %s", typ, t)
 		}
-		return &typeval{typ, val}, nil
+		return &typeval{typ: typ, val: val}, nil
 	}
 }
 
@@ -173,32 +230,64 @@ func checkReturnValue(values []any, types []reflect.Type, isVariadic bool) (retu
 	return returnValues(a), nil
 }
 
-func checkMatcherPattern(values []any, types []reflect.Type, isVariadic bool) ([]condExpr, error) {
+func checkMatcherPattern(values []any, types []reflect.Type, isVariadic bool, o *options) ([]Matcher, error) {
 	if len(values) == 0 && len(types) == 0 {
 		return nil, nil
 	}
+	var tail Matcher
+	if n := len(values); isVariadic && isTail(values, types) {
+		typ := types[len(types)-1]
+		p, err := newMatcher(values[n-1], typ, o)
+		if err != nil {
+			return nil, err
+		}
+		tail = &variadicTail{p, typ}
+		values, types = values[:n-1], flattenVariadicType(types, n-1)
+		isVariadic = false
+	}
 	if isVariadic {
 		types = flattenVariadicType(types, len(values))
 	}
 	if len(values) != len(types) {
 		return nil, fmt.Errorf("number of args/results must match to the function signature: %v vs %v", types, values)
 	}
-	a := make([]condExpr, len(values))
+	a := make([]Matcher, len(values), len(values)+1)
 	for i, v := range values {
-		switch v := v.(type) {
-		case condExpr:
-			a[i] = v
-		default:
-			p, err := checkTypeval(v, types[i])
-			if err != nil {
-				return nil, err
-			}
-			a[i] = p
+		p, err := newArgMatcher(v, types[i], o)
+		if err != nil {
+			return nil, err
 		}
+		a[i] = p
+	}
+	if tail != nil {
+		a = append(a, tail)
 	}
 	return a, nil
 }
 
+// isTail reports whether the last of values is a matcher for the variadic arguments of the function
+// that has parameters of types.
+func isTail(values []any, types []reflect.Type) bool {
+	n := len(values)
+	switch {
+	case n == len(types):
+		return isSliceMatcher(values[n-1])
+	case n > len(types):
+		return isRestMatcher(values[n-1])
+	default:
+		return false
+	}
+}
+
+// newArgMatcher is like newMatcher, but it also accepts a [Capture]
+// because v is placed at the top level of the pattern.
+func newArgMatcher(v any, typ reflect.Type, o *options) (Matcher, error) {
+	if _, ok := v.(capturer); ok {
+		return v.(typeBinder).bindType(typ, o)
+	}
+	return newMatcher(v, typ, o)
+}
+
 func flattenVariadicType(types []reflect.Type, n int) []reflect.Type {
 	if len(types) > n {
 		return types[:len(types)-1]
@@ -207,16 +296,25 @@ func flattenVariadicType(types []reflect.Type, n int) []reflect.Type {
 	last := types[len(types)-1]
 	copy(a, types[:len(types)-1])
 	d := n - len(types) + 1
-	copy(a[len(a)-n:], slices.Repeat([]reflect.Type{last.Elem()}, d))
+	copy(a[len(a)-d:], slices.Repeat([]reflect.Type{last.Elem()}, d))
 	return a
 }
 
+// Priority sets the priority of c.
+// If several conditions match the arguments, the mock function uses the condition with the highest priority.
+// The default priority is 0.
+func (c *Cond[T]) Priority(n int) *Cond[T] {
+	c.priority = n
+	return c
+}
+
 // ReturnOnce adds the return values to the eval queue of the mock function.
 func (c *Cond[T]) ReturnOnce(results ...any) *Cond[T] {
 	types := collectTypes(resultTypes{c.m.fn})
 	a, err := checkReturnValue(results, types, false)
 	if err != nil {
-		panic(err)
+		c.m.fail(err)
+		return c
 	}
 	c.evalq = append(c.evalq, a)
 	return c
@@ -235,35 +333,39 @@ func (c *Cond[T]) PanicOnce(v any) *Cond[T] {
 }
 
 // Return overwrites default behavior of the mock function with results.
-// It panics if any of [Cond.ReturnFunc], [Cond.Panic] and this is called two or more times.
+// It panics, or calls t.Fatal under [WithT], if any of [Cond.ReturnFunc], [Cond.Panic] and this is called two or more times.
 func (c *Cond[T]) Return(results ...any) *Cond[T] {
 	if c.dflt != nil {
-		panic("either Return or Panic called twice for a condition")
+		c.m.fail(msgReturnTwice)
+		return c
 	}
 	types := collectTypes(resultTypes{c.m.fn})
 	a, err := checkReturnValue(results, types, false)
 	if err != nil {
-		panic(err)
+		c.m.fail(err)
+		return c
 	}
 	c.dflt = a
 	return c
 }
 
 // ReturnFunc overwrites default behavior of the mock function with fn.
-// It panics if any of [Cond.Return], [Cond.Panic] and this is called two or more times.
+// It panics, or calls t.Fatal under [WithT], if any of [Cond.Return], [Cond.Panic] and this is called two or more times.
 func (c *Cond[T]) ReturnFunc(fn T) *Cond[T] {
 	if c.dflt != nil {
-		panic("either Return or Panic called twice for a condition")
+		c.m.fail(msgReturnTwice)
+		return c
 	}
 	c.dflt = &evalFunc[T]{fn}
 	return c
 }
 
 // Panic overwrites default behavior of the mock function with panic(v).
-// It panics if any of [Cond.Return], [Cond.ReturnFunc] and this is called two or more times.
+// It panics, or calls t.Fatal under [WithT], if any of [Cond.Return], [Cond.ReturnFunc] and this is called two or more times.
 func (c *Cond[T]) Panic(v any) *Cond[T] {
 	if c.dflt != nil {
-		panic("either Return or Panic called twice for a condition")
+		c.m.fail(msgReturnTwice)
+		return c
 	}
 	c.dflt = &panicObject{v}
 	return c
@@ -288,7 +390,7 @@ func (m *Mock[T]) PanicOnce(v any) *Mock[T] {
 }
 
 // Return is like [Cond.Return] except this overwrites to the default condition.
-// It panics if either [Mock.Return] or [Mock.Panic] is called two or more times.
+// It panics, or calls t.Fatal under [WithT], if either [Mock.Return] or [Mock.Panic] is called two or more times.
 func (m *Mock[T]) Return(results ...any) *Mock[T] {
 	m.dflt.Return(results...)
 	return m
@@ -301,54 +403,108 @@ func (m *Mock[T]) ReturnFunc(fn T) *Mock[T] {
 }
 
 // Panic is like [Cond.Panic] except this overwrites to the default condition.
-// It panics if either [Mock.Return] or [Mock.Panic] is called two or more times.
+// It panics, or calls t.Fatal under [WithT], if either [Mock.Return] or [Mock.Panic] is called two or more times.
 func (m *Mock[T]) Panic(v any) *Mock[T] {
 	m.dflt.Panic(v)
 	return m
 }
 
 // When returns a [Cond].
+//
+// Each of args is either a value compared with the argument or a [Matcher].
 func (m *Mock[T]) When(args ...any) *Cond[T] {
 	types := collectTypes(argTypes{m.fn})
-	pattern, err := checkMatcherPattern(args, types, m.fn.IsVariadic())
+	pattern, err := checkMatcherPattern(args, types, m.fn.IsVariadic(), &m.opts)
 	if err != nil {
-		panic(err)
+		m.fail(err)
+		return &Cond[T]{m: m} // not registered
 	}
 	return m.registerMatcher(pattern)
 }
 
 // Make returns a mock function and its recorder.
+//
+// If the mock is created with [WithT], the recorder verifies expectations of the mock when the test finishes.
 func (m *Mock[T]) Make() (T, *Recorder[T]) {
-	var r Recorder[T]
-	r.nused = make(map[*Cond[T]]int)
+	if m.opts.ambiguityCheck {
+		if err := m.checkAmbiguity(); err != nil {
+			m.fail(err)
+		}
+	}
+	r := &Recorder[T]{
+		m:     m,
+		nused: make(map[*Cond[T]]int),
+	}
+	m.recs = append(m.recs, r)
+	if t := m.opts.t; t != nil {
+		t.Cleanup(func() {
+			t.Helper()
+			r.Verify(t)
+		})
+	}
 	p := reflect.MakeFunc(m.fn, func(args []reflect.Value) []reflect.Value {
 		a := fromValues(args)
 		if m.fn.IsVariadic() {
 			a = flattenVariadic(a)
 		}
 		c := m.lookupCond(a)
-		if c == nil {
+		unmatched := c == nil
+		if unmatched {
 			c = m.dflt
+		} else {
+			c.capture(a)
+		}
+		call := Call[T]{
+			args: args,
+			cond: c,
+			goid: goroutineID(), // walking the stack is slow; do it out of the lock
+			pcs:  m.callers(),
 		}
-		off := r.nused[c]
-
 		r.Lock()
-		r.params = append(r.params, args)
+		off := r.nused[c]
+		gen, i := r.gen, len(r.calls)
+		call.time = time.Now()
+		r.calls = append(r.calls, call)
 		r.nused[c]++
 		r.call++
+		unexpected := r.exceeds(c)
+		r.broadcast()
 		r.Unlock()
 
+		if unexpected {
+			m.opts.t.Errorf("%s: unexpected call with (%s); want %s", c, formatArgs(a), c.expect)
+		}
+
 		ret := c.dflt
 		n := len(c.evalq)
 		if off < n {
 			ret = c.evalq[off]
 		}
+		if unmatched && m.opts.strict {
+			err := m.unmatchedError(a)
+			if t := m.opts.t; t != nil {
+				t.Error(err)
+			} else {
+				ret = &panicObject{err}
+			}
+		}
+		var results []reflect.Value
+		start := time.Now()
+		defer func() {
+			e := recover()
+			r.finish(gen, i, results, e, time.Since(start))
+			if e != nil {
+				panic(e)
+			}
+		}()
 		if ret == nil {
-			return m.zeroReturn()
+			results = m.zeroReturn()
+		} else {
+			results = ret.Eval(args)
 		}
-		return ret.Eval(args)
+		return results
 	})
-	return p.Interface().(T), &r
+	return p.Interface().(T), r
 }
 
 func fromValues(values []reflect.Value) []*typeval {
@@ -374,65 +530,130 @@ func flattenVariadic(a []*typeval) []*typeval {
 }
 
 func (m *Mock[T]) lookupCond(args []*typeval) *Cond[T] {
+	var found *Cond[T]
 	for _, c := range m.conds {
-		if c.isCorrect(args) {
-			return c
+		if c.isCorrect(args) && (found == nil || m.precedes(c, found)) {
+			found = c
+		}
+	}
+	return found
+}
+
+// unmatchedError returns an error that describes args and registered conditions.
+func (m *Mock[T]) unmatchedError(args []*typeval) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "unexpected call with (%s)\n", formatArgs(args))
+	if len(m.conds) == 0 {
+		b.WriteString("no conditions are registered")
+		return errors.New(b.String())
+	}
+	b.WriteString("registered conditions:")
+	for _, c := range m.conds {
+		fmt.Fprintf(&b, "\n\t%s", c)
+	}
+
+	var (
+		closest *Cond[T]
+		diffs   []string
+	)
+	for _, c := range m.conds {
+		a, ok := c.diff(args)
+		if ok && (closest == nil || len(a) < len(diffs)) {
+			closest, diffs = c, a
+		}
+	}
+	if closest != nil {
+		fmt.Fprintf(&b, "\nclosest condition: %s", closest)
+		for _, s := range diffs {
+			fmt.Fprintf(&b, "\n\t%s", s)
+		}
+	}
+	return errors.New(b.String())
+}
+
+// diff returns the descriptions of the arguments that c does not accept.
+// It returns false if the number of args does not match to the pattern of c.
+func (c *Cond[T]) diff(args []*typeval) ([]string, bool) {
+	if !c.acceptsLen(len(args)) {
+		return nil, false
+	}
+	pattern, tail := c.splitPattern()
+	var a []string
+	for i, m := range pattern {
+		if !m.Match(args[i].val.Interface()) {
+			a = append(a, fmt.Sprintf("args[%d]: got %s, want %s", i, args[i], m))
+		}
+	}
+	if n := len(pattern); tail != nil && !tail.matchArgs(args[n:]) {
+		a = append(a, fmt.Sprintf("args[%d:]: got (%s), want %s", n, formatArgs(args[n:]), tail))
+	}
+	return a, true
+}
+
+// precedes reports whether c takes precedence over d, which is registered before c.
+func (m *Mock[T]) precedes(c, d *Cond[T]) bool {
+	if c.priority != d.priority {
+		return c.priority > d.priority
+	}
+	if m.opts.specificity {
+		return c.wildcards() < d.wildcards()
+	}
+	return false
+}
+
+// checkAmbiguity returns an error if there are conditions that the mock cannot determine which one is used.
+func (m *Mock[T]) checkAmbiguity() error {
+	for i, c := range m.conds {
+		for _, d := range m.conds[i+1:] {
+			if m.precedes(d, c) || m.precedes(c, d) {
+				continue
+			}
+			if overlapMatchers(c.pattern, d.pattern) {
+				return fmt.Errorf("ambiguous conditions: %s and %s", c, d)
+			}
 		}
 	}
 	return nil
 }
 
-func (m *Mock[T]) registerMatcher(pattern []condExpr) *Cond[T] {
+func (m *Mock[T]) registerMatcher(pattern []Matcher) *Cond[T] {
 	for _, c := range m.conds {
 		if c.equalPattern(pattern) {
 			return c
 		}
 	}
-	c := &Cond[T]{m, pattern, nil, nil}
+	c := &Cond[T]{m: m, pattern: pattern}
 	m.conds = append(m.conds, c)
 	return c
 }
 
-func (m *Mock[T]) zeroReturn() []reflect.Value {
-	n := m.fn.NumOut()
-	a := make([]reflect.Value, n)
-	for i := range a {
-		a[i] = reflect.Zero(m.fn.Out(i))
+// callers returns the program counters of the caller of the mock function if the mock is created with [WithCallers].
+func (m *Mock[T]) callers() []uintptr {
+	if !m.opts.callers {
+		return nil
 	}
-	return a
+	pcs := make([]uintptr, 64)
+	n := runtime.Callers(3, pcs) // skip runtime.Callers, m.callers and the mock function
+	return pcs[:n]
 }
 
-// Recorder records the statistics of a mock function.
-type Recorder[T any] struct {
-	sync.RWMutex
-
-	call   int64
-	nused  map[*Cond[T]]int
-	params [][]reflect.Value
-}
+const msgReturnTwice = "either Return or Panic called twice for a condition"
 
-// Count returns the call count of the mock function.
-func (r *Recorder[T]) Count() int64 {
-	r.RLock()
-	defer r.RUnlock()
-	return r.call
+// fail reports v to the test if the mock is created with [WithT]. Otherwise it panics with v.
+func (m *Mock[T]) fail(v any) {
+	if t := m.opts.t; t != nil {
+		t.Helper()
+		t.Fatal(v)
+		return
+	}
+	panic(v)
 }
 
-// Replay returns an iterator over all call logs of an mock function.
-// Each call reproduces its situation with function arguments.
-func (r *Recorder[T]) Replay() iter.Seq[func(T)] {
-	return func(yield func(func(T)) bool) {
-		r.RLock()
-		params := r.params
-		r.RUnlock()
-		for _, a := range params {
-			do := func(fn T) {
-				v := reflect.ValueOf(fn)
-				v.Call(a)
-			}
-			if !yield(do) {
-				break
-			}
-		}
+func (m *Mock[T]) zeroReturn() []reflect.Value {
+	n := m.fn.NumOut()
+	a := make([]reflect.Value, n)
+	for i := range a {
+		a[i] = reflect.Zero(m.fn.Out(i))
 	}
+	return a
 }
@@ -1,9 +1,11 @@
 package mofu
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"io"
+	"slices"
 	"testing"
 	"time"
 
@@ -71,7 +73,7 @@ func TestMock_Return(t *testing.T) {
 	t.Run("panic when Return is called twice", func(t *testing.T) {
 		defer func() {
 			e := recover()
-			gt.NotNil(t, e)
+			gt.Equal(t, e, any("either Return or Panic called twice for a condition"))
 		}()
 		m := MockFor[func() int]()
 		m.Return(2)
@@ -252,6 +254,289 @@ func TestRecorder_Replay(t *testing.T) {
 	})
 }
 
+func TestRecorder_Call(t *testing.T) {
+	t.Run("arguments", func(t *testing.T) {
+		m := MockFor[func(string, ...int)]()
+		fn, r := m.Make()
+		fn("a", 1, 2)
+		fn("b")
+		c := r.Call(0)
+		gt.Equal(t, c.NumArg(), 2)
+		gt.Equal(t, c.Arg(0), any("a"))
+		gt.Equal(t, c.Args(), []any{"a", []int{1, 2}})
+		gt.Equal(t, r.Call(1).Args(), []any{"b", []int(nil)})
+	})
+	t.Run("out of range", func(t *testing.T) {
+		defer func() {
+			e := recover()
+			gt.NotNil(t, e)
+		}()
+		m := MockFor[func()]()
+		_, r := m.Make()
+		r.Call(0)
+	})
+}
+
+func TestCall_Results(t *testing.T) {
+	t.Run("results", func(t *testing.T) {
+		m := MockFor[func(string) (int, error)]()
+		m.When("a").ReturnOnce(1, nil)
+		m.ReturnFunc(func(s string) (int, error) {
+			return len(s), io.EOF
+		})
+		fn, r := m.Make()
+		fn("a")
+		fn("abc")
+		fn("a")
+		gt.Equal(t, r.Call(0).Results(), []any{1, nil})
+		gt.Equal(t, r.Call(1).Results(), []any{3, io.EOF})
+		gt.Equal(t, r.Call(2).Results(), []any{0, nil})
+	})
+	t.Run("panic", func(t *testing.T) {
+		m := MockFor[func() int]()
+		m.PanicOnce("fake")
+		fn, r := m.Make()
+		func() {
+			defer func() {
+				e := recover()
+				gt.Equal(t, e, any("fake"))
+			}()
+			fn()
+		}()
+		fn()
+		v, ok := r.Call(0).PanicValue()
+		gt.True(t, ok)
+		gt.Equal(t, v, any("fake"))
+		gt.Nil(t, r.Call(0).Results())
+		_, ok = r.Call(1).PanicValue()
+		gt.False(t, ok)
+		gt.Equal(t, r.Call(1).Results(), []any{0})
+	})
+	t.Run("condition", func(t *testing.T) {
+		m := MockFor[func(string)]()
+		c := m.When("a")
+		fn, r := m.Make()
+		fn("a")
+		fn("b")
+		gt.True(t, r.Call(0).Cond() == c)
+		gt.Nil(t, r.Call(1).Cond())
+	})
+}
+
+func TestCall_Time(t *testing.T) {
+	t.Run("time and duration", func(t *testing.T) {
+		m := MockOf(time.Sleep)
+		m.ReturnFunc(func(d time.Duration) {
+			time.Sleep(d)
+		})
+		sleep, r := m.Make()
+		t0 := time.Now()
+		sleep(10 * time.Millisecond)
+		sleep(0)
+		c0, c1 := r.Call(0), r.Call(1)
+		gt.False(t, c0.Time().Before(t0))
+		gt.False(t, c1.Time().Before(c0.Time().Add(10*time.Millisecond)))
+		gt.True(t, c0.Duration() >= 10*time.Millisecond)
+	})
+	t.Run("goroutine", func(t *testing.T) {
+		m := MockFor[func()]()
+		fn, r := m.Make()
+		fn()
+		fn()
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			fn()
+		}()
+		<-done
+		gt.NotEqual(t, r.Call(0).Goroutine(), 0)
+		gt.Equal(t, r.Call(0).Goroutine(), r.Call(1).Goroutine())
+		gt.NotEqual(t, r.Call(0).Goroutine(), r.Call(2).Goroutine())
+	})
+}
+
+func callFromHelper(fn func()) {
+	fn()
+}
+
+func TestCall_Frames(t *testing.T) {
+	t.Run("with callers", func(t *testing.T) {
+		m := MockFor[func()](WithCallers())
+		fn, r := m.Make()
+		callFromHelper(fn)
+		frames := r.Call(0).Frames()
+		gt.A(t, frames).Longer(0)
+		gt.String(t, frames[0].Function).Equal("github.com/lufia/mofu.callFromHelper")
+
+		var buf bytes.Buffer
+		gt.NoError(t, r.WriteCallers(&buf))
+		gt.String(t, buf.String()).HasPrefix("call #0 on goroutine ")
+		gt.String(t, buf.String()).Contains("github.com/lufia/mofu.callFromHelper\n")
+		gt.String(t, buf.String()).Contains("func_test.go:")
+	})
+	t.Run("without callers", func(t *testing.T) {
+		m := MockFor[func()]()
+		fn, r := m.Make()
+		fn()
+		gt.Nil(t, r.Call(0).Frames())
+	})
+}
+
+func TestRecorder_CountFor(t *testing.T) {
+	t.Run("count", func(t *testing.T) {
+		m := MockFor[func(string) int]()
+		foo := m.When("foo").ReturnOnce(1)
+		bar := m.When("bar")
+		fn, r := m.Make()
+		fn("foo")
+		fn("foo")
+		fn("baz")
+		gt.Equal(t, r.CountFor(foo), 2)
+		gt.Equal(t, r.CountFor(bar), 0)
+		gt.Equal(t, r.CountFor(nil), 1)
+	})
+	t.Run("multiple recorders", func(t *testing.T) {
+		m := MockFor[func(string) int]()
+		foo := m.When("foo").ReturnOnce(1)
+		fn1, r1 := m.Make()
+		fn2, r2 := m.Make()
+		gt.Equal(t, fn1("foo"), 1)
+		gt.Equal(t, fn2("foo"), 1)
+		fn2("foo")
+		gt.Equal(t, r1.CountFor(foo), 1)
+		gt.Equal(t, r2.CountFor(foo), 2)
+	})
+	t.Run("condition of another mock", func(t *testing.T) {
+		defer func() {
+			e := recover()
+			gt.NotNil(t, e)
+		}()
+		m1 := MockFor[func(string)]()
+		m2 := MockFor[func(string)]()
+		_, r := m1.Make()
+		r.CountFor(m2.When("foo"))
+	})
+}
+
+func TestRecorder_Calls(t *testing.T) {
+	m := MockFor[func(int)]()
+	fn, r := m.Make()
+	fn(10)
+	fn(20)
+	var a []int
+	for i, c := range r.Calls() {
+		gt.Equal(t, c.Arg(0), any((i+1)*10))
+		a = append(a, i)
+	}
+	gt.Equal(t, a, []int{0, 1})
+}
+
+func TestArgs(t *testing.T) {
+	t.Run("typed arguments", func(t *testing.T) {
+		m := MockFor[func(context.Context, string, error)]()
+		fn, r := m.Make()
+		fn(context.Background(), "key", nil)
+		gt.Equal(t, Args1[context.Context](r, 0), context.Background())
+		_, s := Args2[context.Context, string](r, 0)
+		gt.Equal(t, s, "key")
+		_, _, err := Args3[context.Context, string, error](r, 0)
+		gt.NoError(t, err)
+	})
+	t.Run("mismatched type", func(t *testing.T) {
+		defer func() {
+			e := recover()
+			gt.NotNil(t, e)
+		}()
+		m := MockFor[func(string)]()
+		fn, r := m.Make()
+		fn("key")
+		Args1[int](r, 0)
+	})
+}
+
+func TestRecorder_Reset(t *testing.T) {
+	m := MockFor[func(string) int]()
+	foo := m.When("foo").ReturnOnce(1).ReturnOnce(2)
+	fn, r := m.Make()
+	gt.Equal(t, fn("foo"), 1)
+	fn("bar")
+	r.Reset()
+	gt.Equal(t, r.Count(), 0)
+	gt.Equal(t, r.CountFor(foo), 0)
+	gt.Equal(t, len(slices.Collect(r.Replay())), 0)
+	gt.Equal(t, fn("foo"), 1)
+	gt.Equal(t, r.Count(), 1)
+	gt.Equal(t, r.Call(0).Arg(0), any("foo"))
+}
+
+func TestRecorder_Since(t *testing.T) {
+	t.Run("after snapshot", func(t *testing.T) {
+		m := MockFor[func(string)]()
+		fn, r := m.Make()
+		fn("setup")
+		s := r.Snapshot()
+		fn("act1")
+		fn("act2")
+		var a []any
+		for i, c := range r.Since(s) {
+			gt.Equal(t, r.Call(i).Arg(0), c.Arg(0))
+			a = append(a, c.Arg(0))
+		}
+		gt.Equal(t, a, []any{"act1", "act2"})
+	})
+	t.Run("reset after snapshot", func(t *testing.T) {
+		m := MockFor[func(string)]()
+		fn, r := m.Make()
+		fn("setup")
+		s := r.Snapshot()
+		r.Reset()
+		fn("act")
+		var a []any
+		for _, c := range r.Since(s) {
+			a = append(a, c.Arg(0))
+		}
+		gt.Equal(t, a, []any{"act"})
+	})
+}
+
+func TestRecorder_WaitForCount(t *testing.T) {
+	t.Run("wait", func(t *testing.T) {
+		m := MockFor[func(int)]()
+		fn, r := m.Make()
+		for i := range 3 {
+			go fn(i)
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		gt.NoError(t, r.WaitForCount(ctx, 3))
+		gt.Equal(t, r.Count(), 3)
+	})
+	t.Run("canceled", func(t *testing.T) {
+		m := MockFor[func()]()
+		fn, r := m.Make()
+		fn()
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		err := r.WaitForCount(ctx, 2)
+		gt.Equal(t, err, context.DeadlineExceeded)
+	})
+}
+
+func TestRecorder_Notify(t *testing.T) {
+	m := MockFor[func()]()
+	fn, r := m.Make()
+	c := r.Notify()
+	select {
+	case <-c:
+		t.Fatal("notified before a call")
+	default:
+	}
+	go fn()
+	<-c
+	gt.Equal(t, r.Count(), 1)
+	gt.True(t, r.Notify() != c)
+}
+
 func TestMock_When(t *testing.T) {
 	t.Run("match", func(t *testing.T) {
 		m := MockFor[func(int) int]()
@@ -309,6 +594,14 @@ func TestMock_When(t *testing.T) {
 		gt.Equal(t, fn(1, 2), "1 2")
 	})
 
+	t.Run("variadic arguments with different length", func(t *testing.T) {
+		m := MockOf(fmt.Sprint)
+		m.When(1, 2).ReturnOnce("1 2")
+		fn, _ := m.Make()
+		gt.Equal(t, fn(1), "")
+		gt.Equal(t, fn(1, 2, 3), "")
+	})
+
 	t.Run("variadic and fixed arguments", func(t *testing.T) {
 		type getObjectFunc func(ctx context.Context, path string, opts ...func())
 		m := MockFor[getObjectFunc]()
@@ -317,4 +610,119 @@ func TestMock_When(t *testing.T) {
 		fn(context.Background(), "file")
 		gt.Number(t, r.Count()).Equal(1)
 	})
+
+	t.Run("variadic arguments after fixed arguments", func(t *testing.T) {
+		m := MockFor[func(string, ...any) string]()
+		m.When("%s-%d", "a", 1).Return("a-1")
+		fn, _ := m.Make()
+		gt.Equal(t, fn("%s-%d", "a", 1), "a-1")
+	})
+}
+
+func TestCond_Priority(t *testing.T) {
+	t.Run("registration order", func(t *testing.T) {
+		m := MockFor[func(string, int) int]()
+		m.When(Any, 1).Return(1)
+		m.When("x", 1).Return(2)
+		fn, _ := m.Make()
+		gt.Equal(t, fn("x", 1), 1)
+	})
+	t.Run("priority", func(t *testing.T) {
+		m := MockFor[func(string, int) int]()
+		m.When(Any, 1).Return(1)
+		m.When("x", 1).Return(2).Priority(1)
+		fn, _ := m.Make()
+		gt.Equal(t, fn("x", 1), 2)
+		gt.Equal(t, fn("y", 1), 1)
+	})
+	t.Run("specificity", func(t *testing.T) {
+		m := MockFor[func(string, int) int](WithSpecificityOrder())
+		m.When(Any, Any).Return(1)
+		m.When(Any, 1).Return(2)
+		m.When("x", 1).Return(3)
+		fn, _ := m.Make()
+		gt.Equal(t, fn("x", 1), 3)
+		gt.Equal(t, fn("y", 1), 2)
+		gt.Equal(t, fn("y", 2), 1)
+	})
+	t.Run("priority wins over specificity", func(t *testing.T) {
+		m := MockFor[func(string, int) int](WithSpecificityOrder())
+		m.When(Any, Any).Return(1).Priority(1)
+		m.When("x", 1).Return(2)
+		fn, _ := m.Make()
+		gt.Equal(t, fn("x", 1), 1)
+	})
+}
+
+func TestWithAmbiguityCheck(t *testing.T) {
+	t.Run("ambiguous", func(t *testing.T) {
+		defer func() {
+			e := recover()
+			gt.NotNil(t, e)
+			gt.String(t, fmt.Sprint(e)).Equal(`ambiguous conditions: When(<any>, 1) and When("x", 1)`)
+		}()
+		m := MockFor[func(string, int) int](WithAmbiguityCheck())
+		m.When(Any, 1).Return(1)
+		m.When("x", 1).Return(2)
+		m.Make()
+	})
+	t.Run("resolved by priority", func(t *testing.T) {
+		m := MockFor[func(string, int) int](WithAmbiguityCheck())
+		m.When(Any, 1).Return(1)
+		m.When("x", 1).Return(2).Priority(1)
+		m.Make()
+	})
+	t.Run("resolved by specificity", func(t *testing.T) {
+		m := MockFor[func(string, int) int](WithAmbiguityCheck(), WithSpecificityOrder())
+		m.When(Any, 1).Return(1)
+		m.When("x", 1).Return(2)
+		m.Make()
+	})
+	t.Run("not overlapped", func(t *testing.T) {
+		m := MockFor[func(string, int) int](WithAmbiguityCheck())
+		m.When("x", 1).Return(1)
+		m.When("y", 1).Return(2)
+		m.When(HasPrefix("x"), 2).Return(2)
+		m.When(HasPrefix("y"), 2).Return(2)
+		m.Make()
+	})
+	t.Run("ambiguous with wildcards", func(t *testing.T) {
+		defer func() {
+			e := recover()
+			gt.NotNil(t, e)
+		}()
+		m := MockFor[func(string, int) int](WithAmbiguityCheck(), WithSpecificityOrder())
+		m.When(Any, 1).Return(1)
+		m.When("x", Any).Return(2)
+		m.Make()
+	})
+	t.Run("ambiguous with variadic arguments", func(t *testing.T) {
+		tests := [][2][]any{
+			{{"INFO", AnyRest}, {"INFO"}},
+			{{"INFO"}, {"INFO", AnyRest}},
+			{{"INFO", AnyRest}, {"INFO", "a", 1}},
+			{{"INFO", Rest(Any)}, {Any, "a"}},
+			{{"INFO", AnyRest}, {Any, Rest("a")}},
+		}
+		for _, tt := range tests {
+			func() {
+				defer func() {
+					e := recover()
+					gt.String(t, fmt.Sprint(e)).Contains("ambiguous conditions")
+				}()
+				m := MockFor[func(string, ...any) string](WithAmbiguityCheck())
+				m.When(tt[0]...).Return("1")
+				m.When(tt[1]...).Return("2")
+				m.Make()
+			}()
+		}
+	})
+	t.Run("not overlapped with variadic arguments", func(t *testing.T) {
+		m := MockFor[func(string, ...any) string](WithAmbiguityCheck())
+		m.When("INFO", Rest("a")).Return("1")
+		m.When("INFO", "b").Return("2")
+		m.When("INFO", Len(2)).Return("3")
+		m.When("DEBUG").Return("4")
+		m.Make()
+	})
 }
@@ -3,8 +3,7 @@ module github.com/lufia/mofu
 go 1.23.0
 
 require (
+	github.com/google/go-cmp v0.5.9
 	github.com/m-mizutani/gt v0.2.1
 	github.com/ovechkin-dm/go-dyno v0.5.3
 )
-
-require github.com/google/go-cmp v0.5.9 // indirect
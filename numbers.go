@@ -0,0 +1,207 @@
+package mofu
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"time"
+)
+
+type rangeMatcher struct {
+	name string
+	args []any
+	typ  reflect.Type
+	vals []reflect.Value // args converted to typ; the tolerance of inDelta is float64 or time.Duration
+}
+
+var timeType = reflect.TypeFor[time.Time]()
+
+// Between returns a [Matcher] that accepts a number or a [time.Time] within lo and hi inclusive.
+//
+// The argument type of the mock function must be an integer, a floating-point number or [time.Time].
+// Lo and hi are converted to the argument type if they are numbers.
+// NaN is neither accepted nor allowed as a bound by any range matcher.
+func Between(lo, hi any) Matcher {
+	return &rangeMatcher{name: "between", args: []any{lo, hi}}
+}
+
+// GreaterThan returns a [Matcher] that accepts a number or a [time.Time] greater than x.
+func GreaterThan(x any) Matcher {
+	return &rangeMatcher{name: "greaterThan", args: []any{x}}
+}
+
+// LessThan returns a [Matcher] that accepts a number or a [time.Time] less than x.
+func LessThan(x any) Matcher {
+	return &rangeMatcher{name: "lessThan", args: []any{x}}
+}
+
+// InDelta returns a [Matcher] that accepts a number or a [time.Time] within eps of x.
+//
+// If the argument type of the mock function is [time.Time], eps must be a [time.Duration].
+func InDelta(x, eps any) Matcher {
+	return &rangeMatcher{name: "inDelta", args: []any{x, eps}}
+}
+
+func (m *rangeMatcher) Match(v any) bool {
+	if m.typ == nil {
+		p, ok := bindDynamic(m, v)
+		return ok && p.Match(v)
+	}
+	p := reflect.ValueOf(v)
+	if !p.IsValid() || p.Type() != m.typ || isNaN(p) {
+		return false
+	}
+	switch m.name {
+	case "between":
+		return compareValues(p, m.vals[0]) >= 0 && compareValues(p, m.vals[1]) <= 0
+	case "greaterThan":
+		return compareValues(p, m.vals[0]) > 0
+	case "lessThan":
+		return compareValues(p, m.vals[0]) < 0
+	case "inDelta":
+		if m.typ == timeType {
+			d := p.Interface().(time.Time).Sub(m.vals[0].Interface().(time.Time))
+			return d.Abs() <= m.vals[1].Interface().(time.Duration)
+		}
+		d := math.Abs(toFloat(p) - toFloat(m.vals[0]))
+		return d <= m.vals[1].Float()
+	default:
+		panic("unknown range")
+	}
+}
+
+func (m *rangeMatcher) Equal(o Matcher) bool {
+	p, ok := o.(*rangeMatcher)
+	if !ok || m.name != p.name || m.typ != p.typ {
+		return false
+	}
+	for i, v := range m.vals {
+		if !v.Equal(p.vals[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func (m *rangeMatcher) String() string {
+	a := make([]string, len(m.args))
+	for i, v := range m.args {
+		a[i] = fmt.Sprint(v)
+	}
+	return fmt.Sprintf("%s(%s)", m.name, strings.Join(a, ", "))
+}
+
+func (m *rangeMatcher) bindType(typ reflect.Type, o *options) (Matcher, error) {
+	if typ != timeType && !isNumber(typ) {
+		return nil, fmt.Errorf("cannot use %s for %s value", m.name, typ)
+	}
+	vals := make([]reflect.Value, len(m.args))
+	for i, v := range m.args {
+		t := typ
+		if m.name == "inDelta" && i == 1 {
+			t = reflect.TypeFor[float64]()
+			if typ == timeType {
+				t = reflect.TypeFor[time.Duration]()
+			}
+		}
+		p, err := convertValue(v, t)
+		if err != nil {
+			return nil, err
+		}
+		if isNaN(p) {
+			return nil, fmt.Errorf("cannot use NaN for %s", m.name)
+		}
+		vals[i] = p
+	}
+	return &rangeMatcher{m.name, m.args, typ, vals}, nil
+}
+
+var _ Matcher = (*rangeMatcher)(nil)
+
+// convertValue converts v to typ if v is a number and typ is a numeric type.
+// It returns an error if the conversion loses the value.
+func convertValue(v any, typ reflect.Type) (reflect.Value, error) {
+	p := reflect.ValueOf(v)
+	if !p.IsValid() {
+		return reflect.Value{}, fmt.Errorf("cannot use nil as %s value", typ)
+	}
+	if p.Type() == typ {
+		return p, nil
+	}
+	if !isNumber(p.Type()) || !isNumber(typ) {
+		return reflect.Value{}, fmt.Errorf("mismatched types %s and %s", typ, p.Type())
+	}
+	c := p.Convert(typ)
+	if !c.Convert(p.Type()).Equal(p) || isNegative(c) != isNegative(p) {
+		return reflect.Value{}, fmt.Errorf("cannot represent %v as %s value", v, typ)
+	}
+	return c, nil
+}
+
+// isNegative reports whether the number v is less than zero.
+func isNegative(v reflect.Value) bool {
+	switch {
+	case v.CanInt():
+		return v.Int() < 0
+	case v.CanFloat():
+		return v.Float() < 0
+	default:
+		return false
+	}
+}
+
+func isNumber(typ reflect.Type) bool {
+	switch typ.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return true
+	case reflect.Float32, reflect.Float64:
+		return true
+	default:
+		return false
+	}
+}
+
+// compareValues compares a and b. Both of them must have the same type.
+func compareValues(a, b reflect.Value) int {
+	if a.Type() == timeType {
+		return a.Interface().(time.Time).Compare(b.Interface().(time.Time))
+	}
+	switch {
+	case a.CanInt():
+		return compare(a.Int(), b.Int())
+	case a.CanUint():
+		return compare(a.Uint(), b.Uint())
+	default:
+		return compare(a.Float(), b.Float())
+	}
+}
+
+func compare[T int64 | uint64 | float64](a, b T) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// isNaN reports whether v is a floating-point NaN, which is out of any range.
+func isNaN(v reflect.Value) bool {
+	return v.CanFloat() && math.IsNaN(v.Float())
+}
+
+func toFloat(v reflect.Value) float64 {
+	switch {
+	case v.CanInt():
+		return float64(v.Int())
+	case v.CanUint():
+		return float64(v.Uint())
+	default:
+		return v.Float()
+	}
+}
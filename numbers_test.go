@@ -0,0 +1,140 @@
+package mofu
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/m-mizutani/gt"
+)
+
+func TestBetween(t *testing.T) {
+	t.Run("duration", func(t *testing.T) {
+		m := MockOf(time.Sleep)
+		m.When(Between(time.Second, 2*time.Second)).ReturnFunc(func(time.Duration) {})
+		m.Panic("out of range")
+		sleep, _ := m.Make()
+		sleep(time.Second)
+		sleep(2 * time.Second)
+		defer func() {
+			e := recover()
+			gt.NotNil(t, e)
+		}()
+		sleep(3 * time.Second)
+	})
+	t.Run("untyped constant", func(t *testing.T) {
+		m := MockFor[func(uint8) int]()
+		m.When(Between(1, 3)).Return(1)
+		fn, _ := m.Make()
+		gt.Equal(t, fn(0), 0)
+		gt.Equal(t, fn(1), 1)
+		gt.Equal(t, fn(3), 1)
+		gt.Equal(t, fn(4), 0)
+	})
+	t.Run("time", func(t *testing.T) {
+		t0 := time.Date(2025, time.March, 20, 0, 0, 0, 0, time.UTC)
+		m := MockFor[func(time.Time) int]()
+		m.When(Between(t0, t0.Add(time.Hour))).Return(1)
+		fn, _ := m.Make()
+		gt.Equal(t, fn(t0.Add(time.Minute)), 1)
+		gt.Equal(t, fn(t0.Add(-time.Minute)), 0)
+	})
+	t.Run("NaN", func(t *testing.T) {
+		m := MockFor[func(float64) int]()
+		m.When(Between(1.0, 2.0)).Return(1)
+		m.When(LessThan(1.0)).Return(2)
+		m.When(GreaterThan(2.0)).Return(3)
+		fn, _ := m.Make()
+		gt.Equal(t, fn(math.NaN()), 0)
+	})
+	t.Run("NaN bound", func(t *testing.T) {
+		defer func() {
+			e := recover()
+			gt.NotNil(t, e)
+		}()
+		m := MockFor[func(float64)]()
+		m.When(Between(math.NaN(), 1.0))
+	})
+	t.Run("lossy conversion", func(t *testing.T) {
+		defer func() {
+			e := recover()
+			gt.NotNil(t, e)
+		}()
+		m := MockFor[func(int)]()
+		m.When(Between(0.5, 1))
+	})
+	t.Run("negative to unsigned", func(t *testing.T) {
+		defer func() {
+			e := recover()
+			gt.NotNil(t, e)
+		}()
+		m := MockFor[func(uint)]()
+		m.When(Between(-5, 5))
+	})
+	t.Run("overflow to signed", func(t *testing.T) {
+		defer func() {
+			e := recover()
+			gt.NotNil(t, e)
+		}()
+		m := MockFor[func(int64)]()
+		m.When(Between(0, uint64(1<<63)))
+	})
+	t.Run("not a number", func(t *testing.T) {
+		defer func() {
+			e := recover()
+			gt.NotNil(t, e)
+		}()
+		m := MockFor[func(string)]()
+		m.When(Between("a", "b"))
+	})
+}
+
+func TestRangeMatcher_Match(t *testing.T) {
+	gt.True(t, Between(1, 2).Match(1))
+	gt.False(t, Between(1, 2).Match(3))
+	gt.True(t, GreaterThan(1).Match(1.5))
+	gt.False(t, LessThan(1).Match("0"))
+	gt.False(t, InDelta(1, 0.1).Match(nil))
+}
+
+func TestGreaterThan(t *testing.T) {
+	m := MockFor[func(float64) int]()
+	m.When(GreaterThan(1)).Return(1)
+	m.When(LessThan(-1)).Return(2)
+	fn, _ := m.Make()
+	gt.Equal(t, fn(1.5), 1)
+	gt.Equal(t, fn(1), 0)
+	gt.Equal(t, fn(-1.5), 2)
+}
+
+func TestInDelta(t *testing.T) {
+	t.Run("float", func(t *testing.T) {
+		m := MockFor[func(float64) int]()
+		m.When(InDelta(0.3, 1e-9)).Return(1)
+		fn, _ := m.Make()
+		gt.Equal(t, fn(0.1+0.2), 1)
+		gt.Equal(t, fn(0.31), 0)
+	})
+	t.Run("integer", func(t *testing.T) {
+		m := MockFor[func(int) int]()
+		m.When(InDelta(10, 2)).Return(1)
+		fn, _ := m.Make()
+		gt.Equal(t, fn(8), 1)
+		gt.Equal(t, fn(13), 0)
+	})
+	t.Run("time", func(t *testing.T) {
+		t0 := time.Date(2025, time.March, 20, 0, 0, 0, 0, time.UTC)
+		m := MockFor[func(time.Time) int]()
+		m.When(InDelta(t0, time.Second)).Return(1)
+		fn, _ := m.Make()
+		gt.Equal(t, fn(t0.Add(-time.Second)), 1)
+		gt.Equal(t, fn(t0.Add(2*time.Second)), 0)
+	})
+	t.Run("same pattern returns the same condition", func(t *testing.T) {
+		m := MockFor[func(time.Duration)]()
+		c := m.When(InDelta(time.Second, time.Millisecond))
+		gt.True(t, c == m.When(InDelta(time.Second, time.Millisecond)))
+		gt.True(t, c != m.When(InDelta(time.Second, time.Microsecond)))
+		gt.String(t, c.pattern[0].String()).Equal("inDelta(1s, 1ms)")
+	})
+}
@@ -0,0 +1,84 @@
+package mofu
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// Option represents an option for [MockFor] and [MockOf].
+type Option func(o *options)
+
+type options struct {
+	cmpOpts        []cmp.Option
+	specificity    bool
+	ambiguityCheck bool
+	callers        bool
+	t              testing.TB
+	strict         bool
+}
+
+// WithCmpOptions makes the mock compare values passed to [Mock.When] with arguments by [cmp.Equal] with opts.
+// Without this option, the mock compares them by [reflect.DeepEqual].
+//
+// The options apply to all values passed to [Mock.When] on the mock.
+// Since cmp.Equal panics on unexported fields that opts do not handle,
+// [Mock.When] fails if it cannot compare a value with opts.
+// The mock function may still panic if an argument holds such fields the value does not,
+// for instance, in a non-nil pointer or an interface.
+func WithCmpOptions(opts ...cmp.Option) Option {
+	return func(o *options) {
+		o.cmpOpts = append(o.cmpOpts, opts...)
+	}
+}
+
+// WithSpecificityOrder makes the mock prefer the most specific condition,
+// which has the fewest wildcards such as [Any], if several conditions of the same priority match the arguments.
+// Without this option, the mock uses the condition registered first.
+func WithSpecificityOrder() Option {
+	return func(o *options) {
+		o.specificity = true
+	}
+}
+
+// WithAmbiguityCheck makes [Mock.Make] panic, or call t.Fatal under [WithT], if the mock has ambiguous conditions.
+//
+// Two conditions are ambiguous if neither takes precedence over the other
+// and there are arguments that both conditions can accept.
+// Conditions are considered to accept the same arguments only if each pair of
+// their matchers at the same position are equal, or one of them is a wildcard or a value the other accepts.
+// A matcher for variadic arguments, such as [AnyRest], is compared with the remaining matchers of the other condition.
+func WithAmbiguityCheck() Option {
+	return func(o *options) {
+		o.ambiguityCheck = true
+	}
+}
+
+// WithCallers makes the mock function capture the stack of its caller on each call.
+// The stack is available by [Call.Frames] and [Recorder.WriteCallers].
+func WithCallers() Option {
+	return func(o *options) {
+		o.callers = true
+	}
+}
+
+// WithT binds the mock to the test t.
+//
+// Errors on configuring the mock are reported by t.Fatal instead of panic.
+// Calls that exceed the expectation, such as [Cond.AtMost], are reported by t.Errorf.
+// Additionally [Mock.Make] registers a cleanup function to t that calls [Recorder.Verify].
+func WithT(t testing.TB) Option {
+	return func(o *options) {
+		o.t = t
+	}
+}
+
+// WithStrict makes the mock function fail on a call that matches no condition registered by [Mock.When].
+//
+// The failure describes the arguments, the registered conditions and the closest one.
+// It is reported by t.Error if the mock is created with [WithT]; otherwise the mock function panics.
+func WithStrict() Option {
+	return func(o *options) {
+		o.strict = true
+	}
+}
@@ -0,0 +1,48 @@
+package mofu
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/m-mizutani/gt"
+)
+
+type cachedItem struct {
+	Name string
+
+	mu    sync.Mutex
+	cache []byte
+}
+
+func TestWithCmpOptions(t *testing.T) {
+	t.Run("ignore unexported fields", func(t *testing.T) {
+		m := MockFor[func(*cachedItem) int](WithCmpOptions(cmpopts.IgnoreUnexported(cachedItem{})))
+		m.When(&cachedItem{Name: "a"}).Return(1)
+		fn, _ := m.Make()
+		gt.Equal(t, fn(&cachedItem{Name: "a", cache: []byte("x")}), 1)
+		gt.Equal(t, fn(&cachedItem{Name: "b"}), 0)
+	})
+	t.Run("nested in matchers", func(t *testing.T) {
+		m := MockFor[func(*cachedItem) int](WithCmpOptions(cmpopts.IgnoreUnexported(cachedItem{})))
+		m.When(Not(&cachedItem{Name: "a"})).Return(1)
+		fn, _ := m.Make()
+		gt.Equal(t, fn(&cachedItem{Name: "a", cache: []byte("x")}), 0)
+		gt.Equal(t, fn(&cachedItem{Name: "b"}), 1)
+	})
+	t.Run("without options", func(t *testing.T) {
+		m := MockFor[func(*cachedItem) int]()
+		m.When(&cachedItem{Name: "a"}).Return(1)
+		fn, _ := m.Make()
+		gt.Equal(t, fn(&cachedItem{Name: "a", cache: []byte("x")}), 0)
+	})
+	t.Run("unexported fields not handled", func(t *testing.T) {
+		defer func() {
+			e := recover()
+			gt.String(t, fmt.Sprint(e)).Contains("cannot compare *mofu.cachedItem")
+		}()
+		m := MockFor[func(string, *cachedItem)](WithCmpOptions(cmpopts.EquateEmpty()))
+		m.When("a", &cachedItem{Name: "a"})
+	})
+}
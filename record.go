@@ -0,0 +1,334 @@
+package mofu
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"iter"
+	"reflect"
+	"runtime"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Recorder records the statistics of a mock function.
+type Recorder[T any] struct {
+	sync.RWMutex
+
+	m     *Mock[T]
+	call  int64
+	nused map[*Cond[T]]int
+	calls []Call[T]
+	gen   int // incremented by Reset
+
+	exceeded map[*Cond[T]]bool // conditions reported as exceeded its expectation
+
+	notify chan struct{} // closed on each call
+}
+
+// Call is a log of a call of the mock function.
+type Call[T any] struct {
+	args []reflect.Value
+	cond *Cond[T]
+
+	time time.Time
+	goid uint64
+	pcs  []uintptr
+
+	done    bool // whether the call has returned or panicked
+	results []reflect.Value
+	panicv  any
+	elapsed time.Duration
+}
+
+// NumArg returns the number of arguments of c.
+// If T is variadic, the variadic arguments are counted as a slice.
+func (c Call[T]) NumArg() int {
+	return len(c.args)
+}
+
+// Arg returns the i'th argument of c.
+// If T is variadic, the last argument is a slice of the variadic arguments.
+func (c Call[T]) Arg(i int) any {
+	return c.args[i].Interface()
+}
+
+// Args returns the arguments of c.
+func (c Call[T]) Args() []any {
+	a := make([]any, len(c.args))
+	for i := range c.args {
+		a[i] = c.Arg(i)
+	}
+	return a
+}
+
+// Results returns the return values of c.
+// It returns nil if the call panicked or has not returned yet.
+func (c Call[T]) Results() []any {
+	if !c.done || c.panicv != nil {
+		return nil
+	}
+	a := make([]any, len(c.results))
+	for i, v := range c.results {
+		a[i] = v.Interface()
+	}
+	return a
+}
+
+// PanicValue returns the value passed to panic if c panicked.
+// Otherwise it returns nil and false.
+func (c Call[T]) PanicValue() (any, bool) {
+	return c.panicv, c.panicv != nil
+}
+
+// Cond returns the condition used for c.
+// It returns nil if c is served by the default condition of the mock.
+func (c Call[T]) Cond() *Cond[T] {
+	if c.cond == c.cond.m.dflt {
+		return nil
+	}
+	return c.cond
+}
+
+// Time returns the time when c is called.
+func (c Call[T]) Time() time.Time {
+	return c.time
+}
+
+// Duration returns the time spent to evaluate the results of c.
+// It returns 0 if the call has not returned yet.
+func (c Call[T]) Duration() time.Duration {
+	return c.elapsed
+}
+
+// Goroutine returns the ID of the goroutine that called c.
+// It is only useful to distinguish goroutines from each other.
+func (c Call[T]) Goroutine() uint64 {
+	return c.goid
+}
+
+// Frames returns the stack frames of the caller of c, except frames in this package and runtime.
+// It returns nil unless the mock is created with [WithCallers].
+func (c Call[T]) Frames() []runtime.Frame {
+	if len(c.pcs) == 0 {
+		return nil
+	}
+	var a []runtime.Frame
+	frames := runtime.CallersFrames(c.pcs)
+	for {
+		f, more := frames.Next()
+		if !isInternalFrame(f) {
+			a = append(a, f)
+		}
+		if !more {
+			break
+		}
+	}
+	return a
+}
+
+// finish records the results of the i'th call in the generation gen.
+// Either results or panicv is available.
+func (r *Recorder[T]) finish(gen, i int, results []reflect.Value, panicv any, elapsed time.Duration) {
+	r.Lock()
+	defer r.Unlock()
+	if gen != r.gen {
+		return // the log was discarded by Reset
+	}
+	r.calls[i].done = true
+	r.calls[i].results = results
+	r.calls[i].panicv = panicv
+	r.calls[i].elapsed = elapsed
+}
+
+// Count returns the call count of the mock function.
+func (r *Recorder[T]) Count() int64 {
+	r.RLock()
+	defer r.RUnlock()
+	return r.call
+}
+
+// Notify returns a channel that is closed when the mock function is called next time.
+// The call may not have returned when the channel is closed.
+//
+// The channel is an edge trigger: it is closed only once, on the first call after Notify.
+// Calls made after that and before the next Notify are not signalled, so a loop of Notify can miss calls.
+// To wait until a number of calls have been made, use [Recorder.WaitForCount], which misses no calls.
+func (r *Recorder[T]) Notify() <-chan struct{} {
+	r.Lock()
+	defer r.Unlock()
+	return r.notifyChan()
+}
+
+// notifyChan returns the channel closed on the next call. The caller must hold the lock.
+func (r *Recorder[T]) notifyChan() chan struct{} {
+	if r.notify == nil {
+		r.notify = make(chan struct{})
+	}
+	return r.notify
+}
+
+// broadcast closes the channel returned by Notify. The caller must hold the lock.
+func (r *Recorder[T]) broadcast() {
+	if r.notify != nil {
+		close(r.notify)
+		r.notify = nil
+	}
+}
+
+// WaitForCount blocks until the mock function has been called n times or more.
+// It returns ctx.Err() if ctx is done before that.
+func (r *Recorder[T]) WaitForCount(ctx context.Context, n int64) error {
+	for {
+		r.Lock()
+		if r.call >= n {
+			r.Unlock()
+			return nil
+		}
+		c := r.notifyChan()
+		r.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-c:
+		}
+	}
+}
+
+// CountFor returns the number of calls that c is used for.
+// If c is nil, it returns the number of calls served by the default condition of the mock.
+// It panics if c is a condition of another mock.
+func (r *Recorder[T]) CountFor(c *Cond[T]) int64 {
+	if c == nil {
+		c = r.m.dflt
+	}
+	if c.m != r.m {
+		panic("the condition is not defined in the mock")
+	}
+	r.RLock()
+	defer r.RUnlock()
+	return int64(r.nused[c])
+}
+
+// Call returns the i'th call log of the mock function.
+// It panics if i is out of range.
+func (r *Recorder[T]) Call(i int) Call[T] {
+	r.RLock()
+	defer r.RUnlock()
+	return r.calls[i]
+}
+
+// Calls returns an iterator over all call logs, with their indexes, of the mock function.
+func (r *Recorder[T]) Calls() iter.Seq2[int, Call[T]] {
+	return r.Since(Snapshot{})
+}
+
+// Snapshot is a checkpoint of the call logs of a [Recorder].
+type Snapshot struct {
+	gen int
+	n   int
+}
+
+// Snapshot returns the current checkpoint of the call logs.
+func (r *Recorder[T]) Snapshot() Snapshot {
+	r.RLock()
+	defer r.RUnlock()
+	return Snapshot{r.gen, len(r.calls)}
+}
+
+// Since returns an iterator over call logs, with their indexes, made after the checkpoint s.
+// If r is reset after s is taken, it returns all call logs.
+func (r *Recorder[T]) Since(s Snapshot) iter.Seq2[int, Call[T]] {
+	return func(yield func(int, Call[T]) bool) {
+		r.RLock()
+		off := s.n
+		if s.gen != r.gen {
+			off = 0
+		}
+		calls := slices.Clone(r.calls[off:])
+		r.RUnlock()
+		for i, c := range calls {
+			if !yield(off+i, c) {
+				break
+			}
+		}
+	}
+}
+
+// Reset clears all call logs and counts of r.
+// The mock function restarts consuming the eval queues of conditions from the top.
+func (r *Recorder[T]) Reset() {
+	r.Lock()
+	defer r.Unlock()
+	r.call = 0
+	r.nused = make(map[*Cond[T]]int)
+	r.calls = nil
+	r.exceeded = nil
+	r.gen++
+}
+
+// WriteCallers writes the stack frames of the callers of all calls to w.
+// Frames are available only if the mock is created with [WithCallers].
+func (r *Recorder[T]) WriteCallers(w io.Writer) error {
+	for i, c := range r.Calls() {
+		if _, err := fmt.Fprintf(w, "call #%d on goroutine %d:\n", i, c.Goroutine()); err != nil {
+			return err
+		}
+		for _, f := range c.Frames() {
+			if _, err := fmt.Fprintf(w, "\t%s\n\t\t%s:%d\n", f.Function, f.File, f.Line); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Replay returns an iterator over all call logs of an mock function.
+// Each call reproduces its situation with function arguments.
+func (r *Recorder[T]) Replay() iter.Seq[func(T)] {
+	return func(yield func(func(T)) bool) {
+		for _, c := range r.Calls() {
+			do := func(fn T) {
+				v := reflect.ValueOf(fn)
+				v.Call(c.args)
+			}
+			if !yield(do) {
+				break
+			}
+		}
+	}
+}
+
+// Args1 returns the first argument of the i'th call of the mock function.
+// It panics if the argument is not assignable to A.
+func Args1[A, T any](r *Recorder[T], i int) A {
+	c := r.Call(i)
+	return argAs[A](c, 0)
+}
+
+// Args2 returns the first two arguments of the i'th call of the mock function.
+// It panics if the arguments are not assignable to A and B respectively.
+func Args2[A, B, T any](r *Recorder[T], i int) (A, B) {
+	c := r.Call(i)
+	return argAs[A](c, 0), argAs[B](c, 1)
+}
+
+// Args3 returns the first three arguments of the i'th call of the mock function.
+// It panics if the arguments are not assignable to A, B and C respectively.
+func Args3[A, B, C, T any](r *Recorder[T], i int) (A, B, C) {
+	c := r.Call(i)
+	return argAs[A](c, 0), argAs[B](c, 1), argAs[C](c, 2)
+}
+
+func argAs[A, T any](c Call[T], i int) A {
+	var a A
+	v := c.args[i]
+	p := reflect.ValueOf(&a).Elem()
+	if !v.Type().AssignableTo(p.Type()) {
+		panic(fmt.Sprintf("cannot use argument %d (%s) as %s value", i, v.Type(), p.Type()))
+	}
+	p.Set(v)
+	return a
+}
@@ -2,6 +2,7 @@ package mofu_test
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/lufia/mofu"
@@ -25,3 +26,25 @@ func ExampleRecorder_Replay() {
 	}
 	// Output: 100ms
 }
+
+func ExampleArgs1() {
+	m := mofu.MockOf(time.Sleep)
+	sleep, r := m.Make()
+	sleep(100 * time.Millisecond)
+	sleep(200 * time.Millisecond)
+	fmt.Println(mofu.Args1[time.Duration](r, 1))
+	// Output: 200ms
+}
+
+func ExampleRecorder_Calls() {
+	m := mofu.MockOf(strings.Repeat)
+	repeat, r := m.Make()
+	repeat("a", 1)
+	repeat("b", 2)
+	for i, c := range r.Calls() {
+		fmt.Println(i, c.Args())
+	}
+	// Output:
+	// 0 [a 1]
+	// 1 [b 2]
+}
@@ -0,0 +1,80 @@
+package mofu
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+type stringMatcher struct {
+	name string
+	arg  string
+	fn   func(s string) bool
+}
+
+// HasPrefix returns a [Matcher] that accepts a string or a byte slice beginning with prefix.
+func HasPrefix(prefix string) Matcher {
+	return &stringMatcher{"hasPrefix", prefix, func(s string) bool {
+		return strings.HasPrefix(s, prefix)
+	}}
+}
+
+// HasSuffix returns a [Matcher] that accepts a string or a byte slice ending with suffix.
+func HasSuffix(suffix string) Matcher {
+	return &stringMatcher{"hasSuffix", suffix, func(s string) bool {
+		return strings.HasSuffix(s, suffix)
+	}}
+}
+
+// Contains returns a [Matcher] that accepts a string or a byte slice containing substr.
+func Contains(substr string) Matcher {
+	return &stringMatcher{"contains", substr, func(s string) bool {
+		return strings.Contains(s, substr)
+	}}
+}
+
+// Regexp returns a [Matcher] that accepts a string or a byte slice matching the regular expression expr.
+// It panics if expr cannot be parsed.
+func Regexp(expr string) Matcher {
+	re := regexp.MustCompile(expr)
+	return &stringMatcher{"regexp", expr, re.MatchString}
+}
+
+func (m *stringMatcher) Match(v any) bool {
+	p := reflect.ValueOf(v)
+	switch {
+	case !p.IsValid():
+		return false
+	case p.Kind() == reflect.String:
+		return m.fn(p.String())
+	case isByteSlice(p.Type()):
+		return m.fn(string(p.Bytes()))
+	default:
+		return false
+	}
+}
+
+func (m *stringMatcher) Equal(o Matcher) bool {
+	p, ok := o.(*stringMatcher)
+	return ok && m.name == p.name && m.arg == p.arg
+}
+
+func (m *stringMatcher) String() string {
+	return fmt.Sprintf("%s(%q)", m.name, m.arg)
+}
+
+func (m *stringMatcher) bindType(typ reflect.Type, o *options) (Matcher, error) {
+	switch {
+	case typ.Kind() == reflect.String, typ.Kind() == reflect.Interface, isByteSlice(typ):
+		return m, nil
+	default:
+		return nil, fmt.Errorf("cannot use %s for %s value", m, typ)
+	}
+}
+
+var _ Matcher = (*stringMatcher)(nil)
+
+func isByteSlice(typ reflect.Type) bool {
+	return typ.Kind() == reflect.Slice && typ.Elem().Kind() == reflect.Uint8
+}
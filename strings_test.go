@@ -0,0 +1,59 @@
+package mofu
+
+import (
+	"testing"
+
+	"github.com/m-mizutani/gt"
+)
+
+type query string
+
+func TestStringMatcher(t *testing.T) {
+	t.Run("string", func(t *testing.T) {
+		m := MockFor[func(string) int]()
+		m.When(HasPrefix("SELECT ")).Return(1)
+		m.When(HasSuffix(";")).Return(2)
+		m.When(Contains("INTO")).Return(3)
+		fn, _ := m.Make()
+		gt.Equal(t, fn("SELECT * FROM t"), 1)
+		gt.Equal(t, fn("DELETE FROM t;"), 2)
+		gt.Equal(t, fn("INSERT INTO t VALUES (1)"), 3)
+		gt.Equal(t, fn("UPDATE t SET a = 1"), 0)
+	})
+	t.Run("byte slice", func(t *testing.T) {
+		m := MockFor[func([]byte) int]()
+		m.When(Contains("error")).Return(1)
+		fn, _ := m.Make()
+		gt.Equal(t, fn([]byte("an error occurred")), 1)
+		gt.Equal(t, fn(nil), 0)
+	})
+	t.Run("named string", func(t *testing.T) {
+		m := MockFor[func(query) int]()
+		m.When(Regexp(`^id=\d+$`)).Return(1)
+		fn, _ := m.Make()
+		gt.Equal(t, fn("id=123"), 1)
+		gt.Equal(t, fn("id=abc"), 0)
+	})
+	t.Run("interface", func(t *testing.T) {
+		m := MockFor[func(any) int]()
+		m.When(HasPrefix("a")).Return(1)
+		fn, _ := m.Make()
+		gt.Equal(t, fn("abc"), 1)
+		gt.Equal(t, fn(1), 0)
+		gt.Equal(t, fn(nil), 0)
+	})
+	t.Run("same pattern returns the same condition", func(t *testing.T) {
+		m := MockFor[func(string)]()
+		gt.True(t, m.When(Regexp("a+")) == m.When(Regexp("a+")))
+		gt.True(t, m.When(HasPrefix("a")) != m.When(HasSuffix("a")))
+		gt.String(t, m.When(Contains("a")).pattern[0].String()).Equal(`contains("a")`)
+	})
+	t.Run("not a string", func(t *testing.T) {
+		defer func() {
+			e := recover()
+			gt.NotNil(t, e)
+		}()
+		m := MockFor[func(int)]()
+		m.When(HasPrefix("1"))
+	})
+}
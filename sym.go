@@ -1,8 +1,10 @@
 package mofu
 
 import (
+	"bytes"
 	"reflect"
 	"runtime"
+	"strconv"
 	"strings"
 )
 
@@ -17,3 +19,38 @@ func funcName(fn reflect.Value) string {
 	s, _ := strings.CutSuffix(name, "-fm")
 	return s
 }
+
+// goroutineID returns the ID of the current goroutine.
+func goroutineID() uint64 {
+	var buf [64]byte
+	n := runtime.Stack(buf[:], false)
+
+	// buf = "goroutine (id) [running]:\n..."
+	b, _ := bytes.CutPrefix(buf[:n], []byte("goroutine "))
+	if i := bytes.IndexByte(b, ' '); i >= 0 {
+		b = b[:i]
+	}
+	id, _ := strconv.ParseUint(string(b), 10, 64)
+	return id
+}
+
+var internalPackages = []string{
+	"github.com/lufia/mofu.",
+	"github.com/ovechkin-dm/go-dyno/",
+	"reflect.",
+	"runtime.",
+}
+
+// isInternalFrame reports whether f is a frame in this package or the runtime.
+// Frames in test files are not internal.
+func isInternalFrame(f runtime.Frame) bool {
+	if strings.HasSuffix(f.File, "_test.go") {
+		return false
+	}
+	for _, s := range internalPackages {
+		if strings.HasPrefix(f.Function, s) {
+			return true
+		}
+	}
+	return false
+}